@@ -1,8 +1,12 @@
 package gnext
 
 import (
+	"encoding"
+	"errors"
 	"fmt"
+	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"io"
 	"reflect"
 	"strconv"
@@ -11,69 +15,136 @@ import (
 // TODO potential optimization: return a pointer to `reflect.value` instead of struct directly
 type argBuilder func(ctx *callContext) (reflect.Value, error)
 
-func stringParamBuilder(paramName string, optional bool) argBuilder {
+var errEmptyParam = errors.New("empty value")
+
+func paramBuilder(paramType reflect.Type, param pathParameter, optional bool) argBuilder {
+	parse := paramParser(paramType)
 	if optional {
+		nilValue := reflect.Zero(reflect.PtrTo(paramType))
 		return func(ctx *callContext) (reflect.Value, error) {
-			param := ctx.rawContext.Param(paramName)
-			if param == "" {
-				return reflect.ValueOf((*string)(nil)), nil
+			value, err := parse(param.value(ctx.rawContext))
+			if err != nil {
+				return nilValue, nil
 			}
-			return reflect.ValueOf(&param), nil
+			ptr := reflect.New(paramType)
+			ptr.Elem().Set(value)
+			return ptr, nil
 		}
 	} else {
 		return func(ctx *callContext) (reflect.Value, error) {
-			param := ctx.rawContext.Param(paramName)
-			if param == "" {
-				return reflect.Value{}, &NotFound{fmt.Errorf("param '%s' does not exist", paramName)}
+			value, err := parse(param.value(ctx.rawContext))
+			if err != nil {
+				return reflect.Value{}, &NotFound{fmt.Errorf("param '%s' does not exist or is not a valid %s: %w", param.name, paramType, err)}
 			}
-			return reflect.ValueOf(param), nil
+			return value, nil
 		}
 	}
 }
 
-func intParamBuilder(paramName string, optional bool) argBuilder {
-	if optional {
-		return func(ctx *callContext) (reflect.Value, error) {
-			number, err := strconv.Atoi(ctx.rawContext.Param(paramName))
-			if err != nil {
-				return reflect.ValueOf((*int)(nil)), nil
+// paramParser returns a function converting a raw path parameter into a value of `paramType`.
+// Types implementing encoding.TextUnmarshaler take precedence over their underlying kind.
+func paramParser(paramType reflect.Type) func(string) (reflect.Value, error) {
+	if reflect.PtrTo(paramType).Implements(textUnmarshalerType) {
+		return func(raw string) (reflect.Value, error) {
+			if raw == "" {
+				return reflect.Value{}, errEmptyParam
 			}
-			return reflect.ValueOf(&number), nil
+			value := reflect.New(paramType)
+			if err := value.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+				return reflect.Value{}, err
+			}
+			return value.Elem(), nil
 		}
-	} else {
-		return func(ctx *callContext) (reflect.Value, error) {
-			number, err := strconv.Atoi(ctx.rawContext.Param(paramName))
+	}
+
+	if !isScalarKind(paramType.Kind()) {
+		panic("unknown param kind: " + paramType.Kind().String())
+	}
+
+	return func(raw string) (reflect.Value, error) {
+		if raw == "" {
+			return reflect.Value{}, errEmptyParam
+		}
+		value := reflect.New(paramType).Elem()
+		switch paramType.Kind() {
+		case reflect.String:
+			value.SetString(raw)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			number, err := strconv.ParseInt(raw, 10, paramType.Bits())
 			if err != nil {
-				return reflect.Value{}, &NotFound{fmt.Errorf("param '%s' does not exist or is not an integer", paramName)}
+				return reflect.Value{}, err
 			}
-			return reflect.ValueOf(number), nil
+			value.SetInt(number)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			number, err := strconv.ParseUint(raw, 10, paramType.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value.SetUint(number)
+		case reflect.Float32, reflect.Float64:
+			number, err := strconv.ParseFloat(raw, paramType.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value.SetFloat(number)
+		case reflect.Bool:
+			boolean, err := strconv.ParseBool(raw)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value.SetBool(boolean)
 		}
+		return value, nil
 	}
 }
 
-func paramBuilder(kind reflect.Kind, paramName string, optional bool) argBuilder {
-	switch kind {
-	case reflect.Int:
-		return intParamBuilder(paramName, optional)
-	case reflect.String:
-		return stringParamBuilder(paramName, optional)
-	default:
-		panic("unknown param kind: " + kind.String())
-	}
+type bindFunc func(ctx *gin.Context, obj interface{}) error
+
+func genericBuilder(bodyType reflect.Type, bindType binding.Binding, validate *validator.Validate) argBuilder {
+	return bindingBuilder(bodyType, func(ctx *gin.Context, obj interface{}) error {
+		return ctx.ShouldBindWith(obj, bindType)
+	}, validate)
 }
 
-func genericBuilder(bodyType reflect.Type, bindType binding.Binding) argBuilder {
+// negotiatedBodyBuilder binds the request body using the binding matching its `Content-Type`.
+// Only the media types able to decode the body type are accepted.
+func negotiatedBodyBuilder(bodyType reflect.Type, mediaTypes mediaTypes, validate *validator.Validate) argBuilder {
+	mediaTypes = mediaTypes.supporting(bodyType)
+	return bindingBuilder(bodyType, func(ctx *gin.Context, obj interface{}) error {
+		bindType, err := mediaTypes.requestBinding(ctx)
+		if err != nil {
+			return err
+		}
+		return ctx.ShouldBindWith(obj, bindType)
+	}, validate)
+}
+
+func uriBuilder(pathType reflect.Type, params pathParameters, validate *validator.Validate) argBuilder {
+	return bindingBuilder(pathType, func(ctx *gin.Context, obj interface{}) error {
+		if err := uriBinding(ctx, params, obj); err != nil {
+			return &NotFound{err}
+		}
+		return nil
+	}, validate)
+}
+
+// bindingBuilder creates the value of given type and binds it with the bind function,
+// validating it with the router validator.
+func bindingBuilder(bodyType reflect.Type, bind bindFunc, validate *validator.Validate) argBuilder {
 	if bodyType.Kind() == reflect.Ptr {
 		bodyType = bodyType.Elem()
 		return func(ctx *callContext) (reflect.Value, error) {
 			value := reflect.New(bodyType)
 
-			if err := ctx.rawContext.ShouldBindWith(value.Interface(), bindType); err != nil {
+			if err := bind(ctx.rawContext, value.Interface()); err != nil {
 				if err == io.EOF {
 					return reflect.New(value.Type()).Elem(), nil
 				}
 				return reflect.Value{}, err
 			}
+			if err := validateValue(validate, value.Interface()); err != nil {
+				return reflect.Value{}, err
+			}
 
 			return value, nil
 		}
@@ -81,7 +152,10 @@ func genericBuilder(bodyType reflect.Type, bindType binding.Binding) argBuilder
 		return func(ctx *callContext) (reflect.Value, error) {
 			value := reflect.New(bodyType)
 
-			if err := ctx.rawContext.ShouldBindWith(value.Interface(), bindType); err != nil {
+			if err := bind(ctx.rawContext, value.Interface()); err != nil {
+				return reflect.Value{}, err
+			}
+			if err := validateValue(validate, value.Interface()); err != nil {
 				return reflect.Value{}, err
 			}
 
@@ -1,6 +1,9 @@
 package gnext
 
-import "reflect"
+import (
+	"net/http"
+	"reflect"
+)
 
 type argSetter func(*reflect.Value, *callContext)
 
@@ -17,6 +20,13 @@ func responseSetter(contextIndex int) argSetter {
 	}
 }
 
+func streamSetter(contextIndex int) argSetter {
+	return func(value *reflect.Value, ctx *callContext) {
+		ctx.values[contextIndex] = value
+		ctx.stream = value
+	}
+}
+
 func errorSetter(value *reflect.Value, ctx *callContext) {
 	if !value.IsNil() {
 		ctx.error = value
@@ -46,17 +56,70 @@ func headersSetter(optional bool) argSetter {
 	}
 }
 
+func cookiesSetter(optional bool) argSetter {
+	if optional {
+		return func(value *reflect.Value, ctx *callContext) {
+			cookies := value.Interface().(*Cookies)
+			if cookies != nil {
+				for _, cookie := range *cookies {
+					http.SetCookie(ctx.rawContext.Writer, cookie)
+				}
+			}
+		}
+	} else {
+		return func(value *reflect.Value, ctx *callContext) {
+			for _, cookie := range value.Interface().(Cookies) {
+				http.SetCookie(ctx.rawContext.Writer, cookie)
+			}
+		}
+	}
+}
+
 func statusSetter(optional bool) argSetter {
 	if optional {
 		return func(value *reflect.Value, ctx *callContext) {
 			statusPtr := value.Interface().(*Status)
 			if statusPtr != nil {
 				ctx.status = *statusPtr
+				ctx.statusSet = true
 			}
 		}
 	} else {
 		return func(value *reflect.Value, ctx *callContext) {
 			ctx.status = value.Interface().(Status)
+			ctx.statusSet = true
+		}
+	}
+}
+
+// abortingResponseSetter sets the response of a nilable type, e.g. a pointer, returned from a before-middleware.
+// If it is not nil, the request handling is stopped and the response is sent with its default status,
+// unless the status is returned explicitly.
+func abortingResponseSetter(contextIndex int, defaultStatus Status) argSetter {
+	return func(value *reflect.Value, ctx *callContext) {
+		if isNilable(value.Kind()) && value.IsNil() {
+			return
+		}
+		ctx.values[contextIndex] = value
+		ctx.responseIndex = contextIndex
+		ctx.aborted = true
+		if !ctx.statusSet {
+			ctx.status = defaultStatus
+		}
+	}
+}
+
+func abortSetter(optional bool) argSetter {
+	if optional {
+		return func(value *reflect.Value, ctx *callContext) {
+			if abort := value.Interface().(*Abort); abort != nil {
+				abort.apply(ctx)
+			}
+		}
+	} else {
+		return func(value *reflect.Value, ctx *callContext) {
+			abort := value.Interface().(Abort)
+			abort.apply(ctx)
 		}
 	}
 }
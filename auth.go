@@ -0,0 +1,281 @@
+package gnext
+
+import (
+	"errors"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/meteran/gnext/docs"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var errInvalidCredentials = errors.New("invalid credentials")
+
+// BearerAuthOptions configures the BearerAuth middleware.
+type BearerAuthOptions struct {
+	// Key verifies the signatures of tokens. Its type selects the accepted algorithms:
+	// []byte for HS256, HS384 and HS512, *rsa.PublicKey for RS* and PS*, *ecdsa.PublicKey for ES*
+	// and ed25519.PublicKey for EdDSA.
+	Key interface{}
+
+	// Keys verify the signatures of tokens having the key id (`kid` header), e.g. to rotate the keys.
+	// Tokens without the key id are verified with Key.
+	Keys map[string]interface{}
+
+	// Issuer, if set, must be equal to the `iss` claim.
+	Issuer string
+
+	// Audience, if set, must be one of the values of the `aud` claim.
+	Audience string
+
+	// Leeway is the tolerance of clock skew when checking the `exp` and `nbf` claims.
+	Leeway time.Duration
+
+	// RequireExpiry rejects tokens without the `exp` claim, which would be valid forever.
+	// If not set, it is enabled, so it has to be disabled explicitly to accept such tokens.
+	RequireExpiry *bool
+
+	// Claims is an example value of the claims type, e.g. `&MyClaims{}`, where MyClaims embeds JWTClaims.
+	// The claims are decoded into a new value of this type and passed to the handlers.
+	// If not set, *JWTClaims are passed.
+	Claims interface{}
+
+	// SchemeName is the name of the security scheme in the documentation.
+	// If not set, the default value is "bearerAuth".
+	SchemeName string
+}
+
+// BearerAuth returns a middleware authenticating requests with a JSON Web Token sent in the `Authorization: Bearer` header.
+// The token signature is verified with the configured keys, as well as its `exp`, `nbf`, `iss` and `aud` claims.
+// Tokens without the `exp` claim are rejected, unless RequireExpiry is disabled.
+// The claims of the token are passed to the handlers, e.g. `func(claims *JWTClaims)`.
+// If the token is missing or invalid, the request fails with *Unauthorized.
+func BearerAuth(options *BearerAuthOptions) Middleware {
+	if options.Key == nil && len(options.Keys) == 0 {
+		panic("bearer auth requires at least one key")
+	}
+	if options.Key != nil {
+		validateJWTKey(options.Key)
+	}
+	for _, key := range options.Keys {
+		validateJWTKey(key)
+	}
+
+	claimsType := reflect.TypeOf(&JWTClaims{})
+	if options.Claims != nil {
+		claimsType = reflect.TypeOf(options.Claims)
+		if !isPtr(claimsType) {
+			claimsType = reflect.PtrTo(claimsType)
+		}
+		if claimsType.Elem().Kind() != reflect.Struct {
+			panic(fmt.Sprintf("JWT claims must be a struct, got %s", claimsType))
+		}
+	}
+
+	if options.SchemeName == "" {
+		options.SchemeName = "bearerAuth"
+	}
+
+	verifier := &jwtVerifier{
+		key:           options.Key,
+		keys:          options.Keys,
+		issuer:        options.Issuer,
+		audience:      options.Audience,
+		leeway:        options.Leeway,
+		requireExpiry: options.RequireExpiry == nil || *options.RequireExpiry,
+		now:           time.Now,
+	}
+
+	security := &docs.SecurityScheme{
+		Name:   options.SchemeName,
+		Scheme: openapi3.NewJWTSecurityScheme(),
+	}
+	return authMiddleware(claimsType, security, func(ctx *gin.Context) (reflect.Value, error) {
+		scheme, token, _ := cut(ctx.GetHeader("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			ctx.Header("WWW-Authenticate", "Bearer")
+			return reflect.Value{}, &Unauthorized{errors.New("missing bearer token")}
+		}
+
+		claims := reflect.New(claimsType.Elem())
+		if err := verifier.verify(token, claims.Interface()); err != nil {
+			// the reason is logged only, so the clients cannot learn how the tokens are verified
+			errLog.Printf("invalid bearer token: %v%s", err, resetColor)
+			ctx.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			return reflect.Value{}, &Unauthorized{errInvalidToken}
+		}
+		return claims, nil
+	})
+}
+
+// APIKeyAuthOptions configures the APIKeyAuth middleware.
+type APIKeyAuthOptions struct {
+	// Name of the header, query parameter or cookie with the key.
+	// If not set, the default value is "X-API-Key".
+	Name string
+
+	// In is the location of the key: "header", "query" or "cookie".
+	// If not set, the default value is "header".
+	In string
+
+	// Validate is a function checking the key and returning the principal passed to the handlers,
+	// e.g. `func(key string) (*Client, error)`. If the principal is nil (or a zero value), the request fails with *Unauthorized.
+	// Errors are handled by the error handlers as usual.
+	Validate interface{}
+
+	// SchemeName is the name of the security scheme in the documentation.
+	// If not set, the default value is "apiKeyAuth".
+	SchemeName string
+}
+
+// APIKeyAuth returns a middleware authenticating requests with a key sent in a header, query parameter or cookie.
+// The key is checked by the Validate function of the options, and the principal it returns is passed to the handlers.
+func APIKeyAuth(options *APIKeyAuthOptions) Middleware {
+	if options.Name == "" {
+		options.Name = "X-API-Key"
+	}
+	if options.In == "" {
+		options.In = openapi3.ParameterInHeader
+	}
+	if options.SchemeName == "" {
+		options.SchemeName = "apiKeyAuth"
+	}
+	name := options.Name
+
+	var readKey func(ctx *gin.Context) string
+	switch options.In {
+	case openapi3.ParameterInHeader:
+		readKey = func(ctx *gin.Context) string { return ctx.GetHeader(name) }
+	case openapi3.ParameterInQuery:
+		readKey = func(ctx *gin.Context) string { return ctx.Query(name) }
+	case openapi3.ParameterInCookie:
+		readKey = func(ctx *gin.Context) string {
+			key, _ := ctx.Cookie(name)
+			return key
+		}
+	default:
+		panic(fmt.Sprintf("unknown API key location: %q, use header, query or cookie", options.In))
+	}
+
+	validate := validateCredentialsFunc(options.Validate, 1)
+	security := &docs.SecurityScheme{
+		Name:   options.SchemeName,
+		Scheme: openapi3.NewSecurityScheme().WithType("apiKey").WithIn(options.In).WithName(name),
+	}
+	return authMiddleware(validate.Type().Out(0), security, func(ctx *gin.Context) (reflect.Value, error) {
+		key := readKey(ctx)
+		if key == "" {
+			return reflect.Value{}, &Unauthorized{errors.New("missing API key")}
+		}
+		return callValidateCredentials(validate, key)
+	})
+}
+
+// BasicAuthOptions configures the BasicAuth middleware.
+type BasicAuthOptions struct {
+	// Validate is a function checking the credentials and returning the principal passed to the handlers,
+	// e.g. `func(username, password string) (*User, error)`. If the principal is nil (or a zero value),
+	// the request fails with *Unauthorized. Errors are handled by the error handlers as usual.
+	Validate interface{}
+
+	// Realm sent in the `WWW-Authenticate` header, when the credentials are missing or invalid.
+	// If not set, the default value is "Restricted".
+	Realm string
+
+	// SchemeName is the name of the security scheme in the documentation.
+	// If not set, the default value is "basicAuth".
+	SchemeName string
+}
+
+// BasicAuth returns a middleware authenticating requests with the HTTP basic authentication.
+// The credentials are checked by the Validate function of the options, and the principal it returns is passed to the handlers.
+func BasicAuth(options *BasicAuthOptions) Middleware {
+	if options.Realm == "" {
+		options.Realm = "Restricted"
+	}
+	if options.SchemeName == "" {
+		options.SchemeName = "basicAuth"
+	}
+
+	validate := validateCredentialsFunc(options.Validate, 2)
+	challenge := fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, options.Realm)
+	security := &docs.SecurityScheme{
+		Name:   options.SchemeName,
+		Scheme: openapi3.NewSecurityScheme().WithType("http").WithScheme("basic"),
+	}
+	return authMiddleware(validate.Type().Out(0), security, func(ctx *gin.Context) (reflect.Value, error) {
+		username, password, ok := ctx.Request.BasicAuth()
+		if !ok {
+			ctx.Header("WWW-Authenticate", challenge)
+			return reflect.Value{}, &Unauthorized{errors.New("missing credentials")}
+		}
+
+		principal, err := callValidateCredentials(validate, username, password)
+		if _, unauthorized := err.(*Unauthorized); unauthorized {
+			ctx.Header("WWW-Authenticate", challenge)
+		}
+		return principal, err
+	})
+}
+
+// authMiddleware creates a before-middleware returning the principal of given type, e.g. `func(*gin.Context) (*User, error)`,
+// so the principal is passed to the next handlers requiring it. The middleware is named after its security scheme.
+func authMiddleware(principalType reflect.Type, security *docs.SecurityScheme, authenticate func(ctx *gin.Context) (reflect.Value, error)) Middleware {
+	beforeType := reflect.FuncOf([]reflect.Type{rawContextType}, []reflect.Type{principalType, errorInterfaceType}, false)
+	before := reflect.MakeFunc(beforeType, func(args []reflect.Value) []reflect.Value {
+		principal, err := authenticate(args[0].Interface().(*gin.Context))
+
+		errValue := reflect.New(errorInterfaceType).Elem()
+		if err != nil {
+			errValue.Set(reflect.ValueOf(err))
+			principal = reflect.Zero(principalType)
+		}
+		return []reflect.Value{principal, errValue}
+	})
+	return Middleware{Name: security.Name, Before: before.Interface(), Security: security}
+}
+
+// validateCredentialsFunc panics if the function doesn't accept given number of strings and return a principal and an error.
+func validateCredentialsFunc(validate interface{}, numIn int) reflect.Value {
+	value := reflect.ValueOf(validate)
+	valueType := reflect.TypeOf(validate)
+	signature := fmt.Sprintf("func(%s) (Principal, error)", strings.TrimSuffix(strings.Repeat("string, ", numIn), ", "))
+	if validate == nil || valueType.Kind() != reflect.Func || valueType.NumIn() != numIn || valueType.NumOut() != 2 {
+		panic(fmt.Sprintf("credentials validation function must be %s, got %T", signature, validate))
+	}
+	for i := 0; i < numIn; i++ {
+		if valueType.In(i).Kind() != reflect.String {
+			panic(fmt.Sprintf("credentials validation function must be %s, got %T", signature, validate))
+		}
+	}
+	if valueType.Out(1) != errorInterfaceType {
+		panic(fmt.Sprintf("credentials validation function must be %s, got %T", signature, validate))
+	}
+	return value
+}
+
+func callValidateCredentials(validate reflect.Value, credentials ...string) (reflect.Value, error) {
+	args := make([]reflect.Value, len(credentials))
+	for i, credential := range credentials {
+		args[i] = reflect.ValueOf(credential).Convert(validate.Type().In(i))
+	}
+
+	results := validate.Call(args)
+	if err, _ := results[1].Interface().(error); err != nil {
+		return reflect.Value{}, err
+	}
+	if results[0].IsZero() {
+		return reflect.Value{}, &Unauthorized{errInvalidCredentials}
+	}
+	return results[0], nil
+}
+
+// cut slices s around the first instance of sep, like strings.Cut.
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
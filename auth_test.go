@@ -0,0 +1,299 @@
+package gnext
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type userClaims struct {
+	JWTClaims
+	Role string `json:"role"`
+}
+
+type apiClient struct {
+	Name string
+}
+
+func signToken(t *testing.T, header map[string]interface{}, claims interface{}, sign func(input []byte) []byte) string {
+	encode := func(value interface{}) string {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	input := encode(header) + "." + encode(claims)
+	return input + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(input)))
+}
+
+func hs256Token(t *testing.T, secret []byte, claims interface{}) string {
+	return signToken(t, map[string]interface{}{"alg": "HS256", "typ": "JWT"}, claims, func(input []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(input)
+		return mac.Sum(nil)
+	})
+}
+
+func TestBearerAuth(t *testing.T) {
+	secret := []byte("secret")
+
+	r := Router()
+	r.Use(BearerAuth(&BearerAuthOptions{
+		Key:      secret,
+		Issuer:   "gnext",
+		Audience: "api",
+		Claims:   &userClaims{},
+	}))
+	r.GET("/me", func(claims *userClaims) string {
+		return claims.Subject + " " + claims.Role
+	})
+
+	future := time.Now().Add(time.Hour).Unix()
+	valid := userClaims{
+		JWTClaims: JWTClaims{Subject: "john", Issuer: "gnext", Audience: JWTAudience{"api"}, ExpiresAt: future},
+		Role:      "admin",
+	}
+	response := makeRequest(t, r, http.MethodGet, "/me", withHeader("Authorization", "Bearer "+hs256Token(t, secret, valid)))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"john admin"`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/me")
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Equal(t, "Bearer", response.Header().Get("WWW-Authenticate"))
+	assert.Contains(t, response.Body.String(), "missing bearer token")
+
+	expired := valid
+	expired.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+	otherAudience := valid
+	otherAudience.Audience = JWTAudience{"other"}
+	otherIssuer := valid
+	otherIssuer.Issuer = "other"
+	noExpiry := valid
+	noExpiry.ExpiresAt = 0
+
+	verifier := &jwtVerifier{key: secret, issuer: "gnext", audience: "api", requireExpiry: true, now: time.Now}
+	for reason, token := range map[string]string{
+		"token is expired":    hs256Token(t, secret, expired),
+		"token has no expiry": hs256Token(t, secret, noExpiry),
+		"invalid audience":    hs256Token(t, secret, otherAudience),
+		"invalid issuer":      hs256Token(t, secret, otherIssuer),
+		"invalid signature":   hs256Token(t, []byte("other secret"), valid),
+		"unsupported algorithm": signToken(t, map[string]interface{}{"alg": "none"}, valid, func([]byte) []byte {
+			return nil
+		}),
+		"malformed token": "not a token",
+	} {
+		err := verifier.verify(token, &userClaims{})
+		require.Error(t, err, reason)
+		assert.Contains(t, err.Error(), reason)
+
+		response = makeRequest(t, r, http.MethodGet, "/me", withHeader("Authorization", "Bearer "+token))
+		assert.Equal(t, http.StatusUnauthorized, response.Code, reason)
+		assert.Equal(t, `Bearer error="invalid_token"`, response.Header().Get("WWW-Authenticate"), reason)
+		assert.Contains(t, response.Body.String(), "invalid token", reason)
+		assert.NotContains(t, response.Body.String(), reason)
+	}
+}
+
+func TestBearerAuthWithoutExpiry(t *testing.T) {
+	secret := []byte("secret")
+	requireExpiry := false
+
+	r := Router()
+	r.Use(BearerAuth(&BearerAuthOptions{Key: secret, RequireExpiry: &requireExpiry}))
+	r.GET("/me", func(claims *JWTClaims) string {
+		return claims.Subject
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/me", withHeader("Authorization", "Bearer "+hs256Token(t, secret, JWTClaims{Subject: "john"})))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"john"`, response.Body.String())
+}
+
+func TestBearerAuthAsymmetricKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ed25519Public, ed25519Private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	r := Router()
+	r.Use(BearerAuth(&BearerAuthOptions{
+		Keys: map[string]interface{}{
+			"rsa":     &rsaKey.PublicKey,
+			"ecdsa":   &ecdsaKey.PublicKey,
+			"ed25519": ed25519Public,
+		},
+	}))
+	r.GET("/me", func(claims *JWTClaims) string {
+		return claims.Subject
+	})
+
+	claims := JWTClaims{Subject: "john", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+	digest := func(input []byte) []byte {
+		sum := sha256.Sum256(input)
+		return sum[:]
+	}
+	tokens := map[string]string{
+		"RS256": signToken(t, map[string]interface{}{"alg": "RS256", "kid": "rsa"}, claims, func(input []byte) []byte {
+			signature, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest(input))
+			require.NoError(t, err)
+			return signature
+		}),
+		"PS256": signToken(t, map[string]interface{}{"alg": "PS256", "kid": "rsa"}, claims, func(input []byte) []byte {
+			signature, err := rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, digest(input), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+			require.NoError(t, err)
+			return signature
+		}),
+		"ES256": signToken(t, map[string]interface{}{"alg": "ES256", "kid": "ecdsa"}, claims, func(input []byte) []byte {
+			r, s, err := ecdsa.Sign(rand.Reader, ecdsaKey, digest(input))
+			require.NoError(t, err)
+			signature := make([]byte, 64)
+			r.FillBytes(signature[:32])
+			s.FillBytes(signature[32:])
+			return signature
+		}),
+		"EdDSA": signToken(t, map[string]interface{}{"alg": "EdDSA", "kid": "ed25519"}, claims, func(input []byte) []byte {
+			return ed25519.Sign(ed25519Private, input)
+		}),
+	}
+	for algorithm, token := range tokens {
+		response := makeRequest(t, r, http.MethodGet, "/me", withHeader("Authorization", "Bearer "+token))
+		assert.Equal(t, http.StatusOK, response.Code, algorithm)
+		assert.Equal(t, `"john"`, response.Body.String(), algorithm)
+	}
+
+	// the public RSA key must not be accepted as an HMAC secret
+	confused := signToken(t, map[string]interface{}{"alg": "HS256", "kid": "rsa"}, claims, func(input []byte) []byte {
+		mac := hmac.New(sha256.New, rsaKey.PublicKey.N.Bytes())
+		mac.Write(input)
+		return mac.Sum(nil)
+	})
+	response := makeRequest(t, r, http.MethodGet, "/me", withHeader("Authorization", "Bearer "+confused))
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	response = makeRequest(t, r, http.MethodGet, "/me", withHeader("Authorization", "Bearer "+hs256Token(t, []byte("secret"), claims)))
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Contains(t, response.Body.String(), "invalid token")
+
+	assert.Panics(t, func() { BearerAuth(&BearerAuthOptions{}) })
+	assert.Panics(t, func() { BearerAuth(&BearerAuthOptions{Key: "secret"}) })
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	r := Router()
+	r.Use(APIKeyAuth(&APIKeyAuthOptions{
+		In:   "query",
+		Name: "api_key",
+		Validate: func(key string) (*apiClient, error) {
+			switch key {
+			case "valid":
+				return &apiClient{Name: "mobile"}, nil
+			case "broken":
+				return nil, fmt.Errorf("storage unavailable")
+			}
+			return nil, nil
+		},
+	}))
+	r.GET("/client", func(client *apiClient) string {
+		return client.Name
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/client?api_key=valid")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"mobile"`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/client?api_key=invalid")
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Contains(t, response.Body.String(), "invalid credentials")
+
+	response = makeRequest(t, r, http.MethodGet, "/client")
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Contains(t, response.Body.String(), "missing API key")
+
+	response = makeRequest(t, r, http.MethodGet, "/client?api_key=broken")
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+
+	assert.Panics(t, func() {
+		APIKeyAuth(&APIKeyAuthOptions{In: "body", Validate: func(string) (*apiClient, error) { return nil, nil }})
+	})
+	assert.Panics(t, func() { APIKeyAuth(&APIKeyAuthOptions{Validate: func(string) *apiClient { return nil }}) })
+}
+
+func TestBasicAuth(t *testing.T) {
+	r := Router()
+	r.Use(BasicAuth(&BasicAuthOptions{
+		Realm: "admin panel",
+		Validate: func(username, password string) (*apiClient, error) {
+			if username == "admin" && password == "secret" {
+				return &apiClient{Name: username}, nil
+			}
+			return nil, nil
+		},
+	}))
+	r.GET("/me", func(client *apiClient) string {
+		return client.Name
+	})
+
+	credentials := func(username, password string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+
+	response := makeRequest(t, r, http.MethodGet, "/me", withHeader("Authorization", credentials("admin", "secret")))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"admin"`, response.Body.String())
+
+	for _, authorization := range []string{"", credentials("admin", "wrong")} {
+		response = makeRequest(t, r, http.MethodGet, "/me", withHeader("Authorization", authorization))
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		assert.Equal(t, `Basic realm="admin panel", charset="UTF-8"`, response.Header().Get("WWW-Authenticate"))
+	}
+}
+
+func TestAuthDocs(t *testing.T) {
+	r := Router()
+	r.GET("/public", func() string { return "" })
+
+	api := r.Group("/api")
+	api.Use(BearerAuth(&BearerAuthOptions{Key: []byte("secret")}))
+	api.GET("/me", func(claims *JWTClaims) string { return "" })
+
+	admin := api.Group("/admin")
+	admin.Use(APIKeyAuth(&APIKeyAuthOptions{SchemeName: "adminKey", Validate: func(string) (*apiClient, error) { return nil, nil }}))
+	admin.GET("/users", func(client *apiClient) string { return "" })
+
+	r.Group("/basic").
+		Use(BasicAuth(&BasicAuthOptions{Validate: func(string, string) (string, error) { return "", nil }})).
+		GET("/me", func() string { return "" })
+
+	doc := generateDocs(t, r)
+
+	schemes := doc.Components.SecuritySchemes
+	require.Len(t, schemes, 3)
+	assert.Equal(t, "http", schemes["bearerAuth"].Value.Type)
+	assert.Equal(t, "bearer", schemes["bearerAuth"].Value.Scheme)
+	assert.Equal(t, "JWT", schemes["bearerAuth"].Value.BearerFormat)
+	assert.Equal(t, "apiKey", schemes["adminKey"].Value.Type)
+	assert.Equal(t, "header", schemes["adminKey"].Value.In)
+	assert.Equal(t, "X-API-Key", schemes["adminKey"].Value.Name)
+	assert.Equal(t, "basic", schemes["basicAuth"].Value.Scheme)
+
+	assert.Nil(t, doc.Paths["/public"].Get.Security)
+	assert.Equal(t, openapi3.SecurityRequirements{{"bearerAuth": []string{}}}, *doc.Paths["/api/me"].Get.Security)
+	assert.Equal(t, openapi3.SecurityRequirements{{"bearerAuth": []string{}, "adminKey": []string{}}}, *doc.Paths["/api/admin/users"].Get.Security)
+	assert.Equal(t, openapi3.SecurityRequirements{{"basicAuth": []string{}}}, *doc.Paths["/basic/me"].Get.Security)
+}
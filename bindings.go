@@ -0,0 +1,460 @@
+package gnext
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// The bindings below decode the request like the Gin ones, but don't validate the result with the global Gin validator.
+// The bound values are validated with the validator of the router instead, see bindingBuilder.
+
+type jsonBinding struct{}
+
+func (jsonBinding) Name() string {
+	return "json"
+}
+
+func (jsonBinding) Bind(req *http.Request, obj interface{}) error {
+	decoder := json.NewDecoder(req.Body)
+	if binding.EnableDecoderUseNumber {
+		decoder.UseNumber()
+	}
+	if binding.EnableDecoderDisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(obj)
+}
+
+type xmlBinding struct{}
+
+func (xmlBinding) Name() string {
+	return "xml"
+}
+
+func (xmlBinding) Bind(req *http.Request, obj interface{}) error {
+	return xml.NewDecoder(req.Body).Decode(obj)
+}
+
+type yamlBinding struct{}
+
+func (yamlBinding) Name() string {
+	return "yaml"
+}
+
+func (yamlBinding) Bind(req *http.Request, obj interface{}) error {
+	return yaml.NewDecoder(req.Body).Decode(obj)
+}
+
+type msgPackBinding struct{}
+
+func (msgPackBinding) Name() string {
+	return "msgpack"
+}
+
+func (msgPackBinding) Bind(req *http.Request, obj interface{}) error {
+	return codec.NewDecoder(req.Body, new(codec.MsgpackHandle)).Decode(obj)
+}
+
+type protoBufBinding struct{}
+
+func (protoBufBinding) Name() string {
+	return "protobuf"
+}
+
+func (protoBufBinding) Bind(req *http.Request, obj interface{}) error {
+	message, ok := obj.(proto.Message)
+	if !ok {
+		return errors.New("obj is not ProtoMessage")
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(body, message)
+}
+
+type formBinding struct{}
+
+func (formBinding) Name() string {
+	return "form-urlencoded"
+}
+
+func (formBinding) Bind(req *http.Request, obj interface{}) error {
+	if err := req.ParseForm(); err != nil {
+		return err
+	}
+	return binding.MapFormWithTag(obj, req.PostForm, "form")
+}
+
+// multipartBinding binds the multipart form values and the uploaded files,
+// which are set to the fields of type *multipart.FileHeader or []*multipart.FileHeader.
+// The form is parsed by Gin, within the MaxMultipartMemory limit of the engine.
+func multipartBinding(ctx *gin.Context, obj interface{}) error {
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return err
+	}
+	if err = binding.MapFormWithTag(obj, form.Value, "form"); err != nil {
+		return err
+	}
+	if value := reflect.ValueOf(obj); value.Kind() == reflect.Ptr && value.Elem().Kind() == reflect.Struct {
+		mapFiles(value.Elem(), form.File)
+	}
+	return nil
+}
+
+func mapFiles(value reflect.Value, files map[string][]*multipart.FileHeader) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			mapFiles(value.Field(i), files)
+			continue
+		}
+
+		headers := files[strings.Split(field.Tag.Get("form"), ",")[0]]
+		if len(headers) == 0 {
+			continue
+		}
+		switch field.Type {
+		case fileHeaderPtrType:
+			value.Field(i).Set(reflect.ValueOf(headers[0]))
+		case fileHeadersType:
+			value.Field(i).Set(reflect.ValueOf(headers))
+		}
+	}
+}
+
+// headerBinding binds the request headers, using `header` tags as header names.
+type headerBinding struct{}
+
+func (headerBinding) Name() string {
+	return "header"
+}
+
+func (headerBinding) Bind(req *http.Request, obj interface{}) error {
+	values := map[string][]string{}
+	collectHeaders(reflect.TypeOf(obj), req.Header, values)
+	if err := binding.MapFormWithTag(obj, values, "header"); err != nil {
+		return &BadRequest{err}
+	}
+	return nil
+}
+
+// collectHeaders gets the values of the headers named in the `header` tags of the struct, keyed by the tag names,
+// so they match regardless of the header name case.
+func collectHeaders(t reflect.Type, header http.Header, values map[string][]string) {
+	t = directType(t)
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			collectHeaders(field.Type, header, values)
+			continue
+		}
+		name := strings.Split(field.Tag.Get("header"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		if headerValues := header.Values(name); len(headerValues) > 0 {
+			values[name] = headerValues
+		}
+	}
+}
+
+// uriBinding binds the path parameters, using `uri` tags as parameter names.
+// Catch-all values are bound without the leading slash, just like positional path params.
+func uriBinding(ctx *gin.Context, params pathParameters, obj interface{}) error {
+	values := make(map[string][]string, len(params))
+	for _, param := range params {
+		values[param.name] = []string{param.value(ctx)}
+	}
+	return binding.MapFormWithTag(obj, values, "uri")
+}
+
+// cookieBinding binds request cookies into a struct, using `cookie` tags as cookie names.
+// If the target is Cookies itself, it gets all the request cookies.
+type cookieBinding struct{}
+
+func (cookieBinding) Name() string {
+	return "cookie"
+}
+
+func (cookieBinding) Bind(req *http.Request, obj interface{}) error {
+	if cookies, ok := obj.(*Cookies); ok {
+		*cookies = req.Cookies()
+		return nil
+	}
+
+	values := map[string][]string{}
+	for _, cookie := range req.Cookies() {
+		values[cookie.Name] = append(values[cookie.Name], cookie.Value)
+	}
+
+	if err := binding.MapFormWithTag(obj, values, "cookie"); err != nil {
+		return &BadRequest{err}
+	}
+	return nil
+}
+
+// queryBinding binds the query parameters like binding.Query, and additionally supports deep objects:
+// the struct and map fields tagged with `form` are bound from parameters like `filter[status]=new`.
+// Deep objects are bound only this way, e.g. `status=new` doesn't set the status of the filter.
+type queryBinding struct{}
+
+func (queryBinding) Name() string {
+	return "query"
+}
+
+func (queryBinding) Bind(req *http.Request, obj interface{}) error {
+	values := req.URL.Query()
+	value := reflect.ValueOf(obj)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		if err := binding.MapFormWithTag(obj, values, "form"); err != nil {
+			return &BadRequest{err}
+		}
+		return nil
+	}
+
+	names := map[string]bool{}
+	flatParameters(value.Elem().Type(), names)
+	flatValues := make(map[string][]string, len(names))
+	for name := range names {
+		if parameter, exists := values[name]; exists {
+			flatValues[name] = parameter
+		}
+	}
+
+	if err := binding.MapFormWithTag(obj, flatValues, "form"); err != nil {
+		return &BadRequest{err}
+	}
+	if err := mapDeepObjects(value.Elem(), values); err != nil {
+		return &BadRequest{err}
+	}
+	return nil
+}
+
+// flatParameters collects the names of the query parameters bound directly to the fields of the struct,
+// i.e. all of them except deep objects. Like in Gin, fields of embedded and untagged structs are flattened.
+func flatParameters(t reflect.Type, names map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		fieldType := directType(field.Type)
+		switch {
+		case name == "-":
+		case name == "" && fieldType.Kind() == reflect.Struct && isDeepObject(fieldType):
+			flatParameters(fieldType, names)
+		case name == "":
+			names[field.Name] = true
+		case !isDeepObject(fieldType):
+			names[name] = true
+		}
+	}
+}
+
+// mapDeepObjects sets the struct and map fields of the struct from the values in the deep object format.
+// Values of nested structs can be deep objects as well, e.g. `filter[price][min]=10`.
+func mapDeepObjects(value reflect.Value, values map[string][]string) error {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("form")
+		name := strings.Split(tag, ",")[0]
+		fieldValue := value.Field(i)
+		fieldType := directType(field.Type)
+
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && name == "" {
+			if fieldValue.Kind() == reflect.Ptr && (fieldValue.IsNil() || field.PkgPath != "") {
+				continue
+			}
+			if err := mapDeepObjects(reflect.Indirect(fieldValue), values); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" || name == "-" || field.PkgPath != "" || !isDeepObject(fieldType) {
+			continue
+		}
+
+		// the fields of nested structs may be set already from parameters of the same name, e.g. `status` of the query,
+		// but they should get only their deep object properties, so they are bound from scratch
+		fieldValue.Set(reflect.Zero(field.Type))
+		properties := deepObjectProperties(values, name)
+		if len(properties) == 0 && fieldValue.Kind() != reflect.Struct {
+			continue
+		}
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				fieldValue.Set(reflect.New(fieldType))
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		var err error
+		if fieldType.Kind() == reflect.Map {
+			err = mapDeepObjectMap(fieldValue, properties)
+		} else if err = binding.MapFormWithTag(fieldValue.Addr().Interface(), properties, "form"); err == nil {
+			err = mapDeepObjects(fieldValue, properties)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid query parameter '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// isDeepObject reports whether the type can be bound from a deep object:
+// a struct or a map with string keys and scalar values, or slices of them.
+func isDeepObject(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+	case reflect.Map:
+		elem := t.Elem()
+		if elem.Kind() == reflect.Slice {
+			elem = elem.Elem()
+		}
+		return t.Key().Kind() == reflect.String && isParsable(elem)
+	default:
+		return false
+	}
+}
+
+func isParsable(t reflect.Type) bool {
+	return isScalarKind(t.Kind()) || reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// deepObjectProperties returns the values of the deep object with given name, keyed by property names,
+// e.g. `filter[status]=new` and `filter[price][min]=10` become `status=new` and `price[min]=10`.
+func deepObjectProperties(values map[string][]string, name string) map[string][]string {
+	properties := map[string][]string{}
+	prefix := name + "["
+	for key, value := range values {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		end := strings.Index(key, "]")
+		if end <= len(prefix) {
+			continue
+		}
+		property := key[len(prefix):end] + key[end+1:]
+		properties[property] = append(properties[property], value...)
+	}
+	return properties
+}
+
+func mapDeepObjectMap(mapValue reflect.Value, properties map[string][]string) error {
+	mapType := mapValue.Type()
+	if mapValue.IsNil() {
+		mapValue.Set(reflect.MakeMapWithSize(mapType, len(properties)))
+	}
+
+	elemType := mapType.Elem()
+	itemType := elemType
+	if elemType.Kind() == reflect.Slice {
+		itemType = elemType.Elem()
+	}
+	parse := paramParser(itemType)
+
+	for key, raw := range properties {
+		if strings.Contains(key, "[") {
+			continue
+		}
+
+		items := reflect.MakeSlice(reflect.SliceOf(itemType), 0, len(raw))
+		for _, item := range raw {
+			var parsed reflect.Value
+			if itemType.Kind() == reflect.String {
+				parsed = reflect.ValueOf(item).Convert(itemType)
+			} else {
+				var err error
+				if parsed, err = parse(item); err != nil {
+					return fmt.Errorf("invalid value of '%s': %w", key, err)
+				}
+			}
+			items = reflect.Append(items, parsed)
+		}
+
+		mapKey := reflect.ValueOf(key).Convert(mapType.Key())
+		if elemType.Kind() == reflect.Slice {
+			mapValue.SetMapIndex(mapKey, items)
+		} else if items.Len() > 0 {
+			mapValue.SetMapIndex(mapKey, items.Index(items.Len()-1))
+		}
+	}
+	return nil
+}
+
+// newValidator creates the validator of a router, reading rules from the `binding` tags, just like Gin does.
+// If jsonTagNames is set, validation errors use field names from `json` tags.
+func newValidator(jsonTagNames bool) *validator.Validate {
+	validate := validator.New()
+	validate.SetTagName("binding")
+
+	if jsonTagNames {
+		validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+			name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+
+			if name == "-" {
+				return ""
+			}
+
+			return name
+		})
+	}
+	return validate
+}
+
+// validateValue validates structs, pointers to them and their slices, the same way as the default Gin validator.
+func validateValue(validate *validator.Validate, obj interface{}) error {
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return validateValue(validate, value.Elem().Interface())
+	case reflect.Struct:
+		return validate.Struct(obj)
+	case reflect.Slice, reflect.Array:
+		var errs binding.SliceValidationError
+		for i := 0; i < value.Len(); i++ {
+			if err := validateValue(validate, value.Index(i).Interface()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		return errs
+	default:
+		return nil
+	}
+}
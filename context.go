@@ -2,6 +2,7 @@ package gnext
 
 import (
 	"github.com/gin-gonic/gin"
+	"os"
 	"reflect"
 )
 
@@ -11,4 +12,19 @@ type callContext struct {
 	error         *reflect.Value
 	status        Status
 	responseIndex int
+	stream        *reflect.Value
+	streamed      bool
+	// aborted is set when a before-middleware stops the request handling, see Abort
+	aborted bool
+	// statusSet is set when a handler returns the status explicitly
+	statusSet bool
+	// file is opened for the File response, see fileResponseSetter
+	file *os.File
+}
+
+// closeFile closes the file opened for the response, in case it is not sent, e.g. because of an error.
+func (ctx *callContext) closeFile() {
+	if ctx.file != nil {
+		_ = ctx.file.Close()
+	}
 }
@@ -0,0 +1,134 @@
+package gnext
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type sessionCookies struct {
+	Cookies
+	SessionId string `cookie:"session_id" binding:"required"`
+	Theme     string `cookie:"theme" binding:"omitempty,oneof=light dark"`
+	Language  string `cookie:"lang,default=en" binding:"required_with=Theme"`
+}
+
+func TestCookiesBinding(t *testing.T) {
+	r := Router()
+	r.GET("/session", func(cookies *sessionCookies) string {
+		return cookies.SessionId + " " + cookies.Theme
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/session", withCookies(
+		&http.Cookie{Name: "session_id", Value: "abc"},
+		&http.Cookie{Name: "theme", Value: "dark"},
+	))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"abc dark"`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/session", withCookies(&http.Cookie{Name: "theme", Value: "dark"}))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = makeRequest(t, r, http.MethodGet, "/session", withCookies(
+		&http.Cookie{Name: "session_id", Value: "abc"},
+		&http.Cookie{Name: "theme", Value: "blue"},
+	))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestInvalidTypedCookie(t *testing.T) {
+	type visitCookies struct {
+		Cookies
+		Visits int `cookie:"visits"`
+	}
+
+	r := Router()
+	r.GET("/visits", func(cookies *visitCookies) int {
+		return cookies.Visits
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/visits", withCookies(&http.Cookie{Name: "visits", Value: "3"}))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `3`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/visits", withCookies(&http.Cookie{Name: "visits", Value: "many"}))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestRawCookies(t *testing.T) {
+	r := Router()
+	r.GET("/cookies", func(cookies Cookies) int {
+		return len(cookies)
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/cookies", withCookies(
+		&http.Cookie{Name: "a", Value: "1"},
+		&http.Cookie{Name: "b", Value: "2"},
+	))
+	assert.Equal(t, `2`, response.Body.String())
+}
+
+func TestReturnCookies(t *testing.T) {
+	r := Router()
+	r.Use(Middleware{
+		After: func() *Cookies {
+			return &Cookies{{Name: "from_middleware", Value: "1"}}
+		},
+	})
+	r.POST("/login", func() (Cookies, Status) {
+		return Cookies{{Name: "session_id", Value: "abc", HttpOnly: true}}, http.StatusNoContent
+	})
+
+	response := makeRequest(t, r, http.MethodPost, "/login")
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	cookies := response.Result().Cookies()
+	require.Len(t, cookies, 2)
+	assert.Equal(t, "session_id", cookies[0].Name)
+	assert.Equal(t, "abc", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, "from_middleware", cookies[1].Name)
+}
+
+func TestCookiesDocs(t *testing.T) {
+	r := Router()
+	r.GET("/session", func(cookies *sessionCookies) {})
+
+	doc := generateDocs(t, r)
+
+	params := doc.Paths["/session"].Get.Parameters
+	assert.Len(t, params, 3)
+
+	sessionId := params.GetByInAndName("cookie", "session_id")
+	require.NotNil(t, sessionId)
+	assert.True(t, sessionId.Required)
+	assert.Equal(t, "string", sessionId.Schema.Value.Type)
+
+	theme := params.GetByInAndName("cookie", "theme")
+	require.NotNil(t, theme)
+	assert.False(t, theme.Required)
+	assert.Equal(t, []interface{}{"light", "dark"}, theme.Schema.Value.Enum)
+
+	language := params.GetByInAndName("cookie", "lang")
+	require.NotNil(t, language)
+	assert.False(t, language.Required)
+	assert.Equal(t, "en", language.Schema.Value.Default)
+}
+
+func TestCookiesDocsAliases(t *testing.T) {
+	type aliasedCookies struct {
+		Cookies
+		Token string `cookie:"token" binding:"mandatory"`
+	}
+
+	r := Router()
+	r.RegisterAlias("mandatory", "required,min=8")
+	r.GET("/token", func(cookies *aliasedCookies) {})
+
+	doc := generateDocs(t, r)
+	token := doc.Paths["/token"].Get.Parameters.GetByInAndName("cookie", "token")
+	require.NotNil(t, token)
+	assert.True(t, token.Required)
+	assert.Equal(t, uint64(8), token.Schema.Value.MinLength)
+}
@@ -0,0 +1,120 @@
+package docs
+
+import (
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Describer is implemented by types describing themselves in the documentation, e.g. enums explaining their values.
+type Describer interface {
+	Describe() string
+}
+
+// Exampler is implemented by types providing an example value for the documentation.
+type Exampler interface {
+	Example() interface{}
+}
+
+var (
+	describerType = reflect.TypeOf((*Describer)(nil)).Elem()
+	examplerType  = reflect.TypeOf((*Exampler)(nil)).Elem()
+)
+
+// applyTypeAnnotations sets the description and the example of the schema from the Describer and Exampler methods of the type.
+func applyTypeAnnotations(schema *openapi3.Schema, type_ reflect.Type) {
+	value := reflect.New(type_)
+	if value.Type().Implements(describerType) {
+		schema.Description = value.Interface().(Describer).Describe()
+	}
+	if value.Type().Implements(examplerType) {
+		schema.Example = value.Interface().(Exampler).Example()
+	}
+}
+
+// hasFieldAnnotations reports whether the field has any of the `doc`, `title`, `example` or `deprecated` tags.
+func hasFieldAnnotations(field reflect.StructField) bool {
+	for _, tag := range []string{docTag, titleTag, exampleTag, deprecatedTag} {
+		if _, exists := field.Tag.Lookup(tag); exists {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFieldAnnotations sets the description, title, example and deprecation of the field schema from its tags.
+// The example is converted to the type of the field, so numbers, booleans and JSON arrays or objects are documented as such.
+func applyFieldAnnotations(schema *openapi3.Schema, field reflect.StructField, exampleType reflect.Type) {
+	if description, exists := field.Tag.Lookup(docTag); exists {
+		schema.Description = description
+	}
+	if title, exists := field.Tag.Lookup(titleTag); exists {
+		schema.Title = title
+	}
+	if example, exists := field.Tag.Lookup(exampleTag); exists {
+		schema.Example = parseTagValue(example, exampleType)
+	}
+	if isDeprecated(field) {
+		schema.Deprecated = true
+	}
+}
+
+// applyParamAnnotations sets the description, example and deprecation of the parameter from the field tags.
+func applyParamAnnotations(param *openapi3.Parameter, field reflect.StructField) {
+	if description, exists := field.Tag.Lookup(docTag); exists {
+		param.Description = description
+	}
+	if example, exists := field.Tag.Lookup(exampleTag); exists {
+		param.Example = parseTagValue(example, field.Type)
+	}
+	if isDeprecated(field) {
+		param.Deprecated = true
+	}
+}
+
+func isDeprecated(field reflect.StructField) bool {
+	deprecated, _ := strconv.ParseBool(field.Tag.Get(deprecatedTag))
+	return deprecated
+}
+
+// parseTagValue converts the value from the tag, e.g. an example, to the value of given type.
+// Arrays and objects are given as JSON. If the conversion fails, the value is used as a string.
+func parseTagValue(value string, type_ reflect.Type) interface{} {
+	type_ = directType(type_)
+	switch type_.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if parsed, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return parsed
+		}
+	case reflect.Float32, reflect.Float64:
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	case reflect.Bool:
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		var parsed interface{}
+		if type_ != timeType && json.Unmarshal([]byte(value), &parsed) == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+// parseDefaultValue converts the default value from the tag to the value of given type.
+// Gin uses the default of a slice as its only item, unless it is given as a JSON array.
+func parseDefaultValue(value string, type_ reflect.Type) interface{} {
+	type_ = directType(type_)
+	if kind := type_.Kind(); (kind == reflect.Slice || kind == reflect.Array) && !strings.HasPrefix(value, "[") {
+		return []interface{}{parseTagValue(value, type_.Elem())}
+	}
+	return parseTagValue(value, type_)
+}
@@ -0,0 +1,252 @@
+package docs
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// formats maps the validator tags to the OpenAPI formats of string values.
+var formats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid3":    "uuid",
+	"uuid4":    "uuid",
+	"uuid5":    "uuid",
+	"ip":       "ip",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"base64":   "byte",
+}
+
+// patterns maps the validator tags to the regular expressions matching the same strings.
+var patterns = map[string]string{
+	"alpha":       "^[a-zA-Z]+$",
+	"alphanum":    "^[a-zA-Z0-9]+$",
+	"numeric":     "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":      "^[0-9]+$",
+	"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+	"hexcolor":    "^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$",
+	"lowercase":   "^[^A-Z]*$",
+	"uppercase":   "^[^a-z]*$",
+}
+
+var oneOfValueRegExp = regexp.MustCompile(`'[^']*'|\S+`)
+
+// applyBindingConstraints translates validation rules from the `binding` tag into the schema constraints.
+// Aliases are expanded and custom validations are documented with the registered functions.
+// Rules following `dive` apply to the items of arrays or the values of maps.
+// Other unknown rules are ignored, as they have no OpenAPI equivalent.
+func (s *Schemas) applyBindingConstraints(schema *openapi3.Schema, rules string) {
+	rulesList := strings.Split(s.expandAliases(rules), ",")
+	for i := 0; i < len(rulesList); i++ {
+		rule := strings.TrimSpace(rulesList[i])
+		name, param := splitRule(rule)
+		if document, isCustom := s.validations[name]; isCustom {
+			addValidationExtension(schema, rule)
+			if document != nil {
+				document(schema, param)
+			}
+			continue
+		}
+
+		switch name {
+		case "dive":
+			if items := itemsSchema(schema); items != nil {
+				s.applyBindingConstraints(items, strings.Join(skipKeys(rulesList[i+1:]), ","))
+			}
+			return
+		case "min", "gte":
+			setLowerBound(schema, param, false)
+		case "max", "lte":
+			setUpperBound(schema, param, false)
+		case "gt":
+			setLowerBound(schema, param, true)
+		case "lt":
+			setUpperBound(schema, param, true)
+		case "len":
+			setLowerBound(schema, param, false)
+			setUpperBound(schema, param, false)
+		case "oneof":
+			for _, value := range oneOfValueRegExp.FindAllString(param, -1) {
+				schema.Enum = append(schema.Enum, enumValue(schema, strings.Trim(value, "'")))
+			}
+		case "unique":
+			if schema.Type == openapi3.TypeArray {
+				schema.UniqueItems = true
+			}
+		case "startswith":
+			setPattern(schema, "^"+regexp.QuoteMeta(param))
+		case "endswith":
+			setPattern(schema, regexp.QuoteMeta(param)+"$")
+		case "contains":
+			setPattern(schema, regexp.QuoteMeta(param))
+		default:
+			if format, ok := formats[name]; ok && schema.Type == openapi3.TypeString {
+				schema.Format = format
+			} else if pattern, ok := patterns[name]; ok && schema.Type == openapi3.TypeString {
+				setPattern(schema, pattern)
+			}
+		}
+	}
+}
+
+// itemsSchema returns the inline schema of array items or map values, or nil for other types.
+// Referenced components are not returned, as their constraints would apply to all usages.
+func itemsSchema(schema *openapi3.Schema) *openapi3.Schema {
+	items := schema.Items
+	if items == nil {
+		items = schema.AdditionalProperties.Schema
+	}
+	if items == nil || items.Ref != "" {
+		return nil
+	}
+	return items.Value
+}
+
+// skipKeys removes the rules of map keys, enclosed between `keys` and `endkeys`, as keys have no schema.
+func skipKeys(rules []string) []string {
+	var result []string
+	inKeys := false
+	for _, rule := range rules {
+		switch strings.TrimSpace(rule) {
+		case "keys":
+			inKeys = true
+		case "endkeys":
+			inKeys = false
+		default:
+			if !inKeys {
+				result = append(result, rule)
+			}
+		}
+	}
+	return result
+}
+
+// enumValue converts the value of `oneof` rule to the type of the schema.
+func enumValue(schema *openapi3.Schema, value string) interface{} {
+	switch schema.Type {
+	case openapi3.TypeInteger:
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return number
+		}
+	case openapi3.TypeNumber:
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	}
+	return value
+}
+
+// setPattern sets the pattern of a string schema. OpenAPI allows one pattern only, so the first one wins.
+func setPattern(schema *openapi3.Schema, pattern string) {
+	if schema.Type == openapi3.TypeString && schema.Pattern == "" {
+		schema.Pattern = pattern
+	}
+}
+
+// expandAliases replaces the registered aliases in the rules with the tags they stand for.
+func (s *Schemas) expandAliases(rules string) string {
+	if len(s.aliases) == 0 {
+		return rules
+	}
+	expanded := strings.Split(rules, ",")
+	for i, rule := range expanded {
+		if tags, isAlias := s.aliases[strings.TrimSpace(rule)]; isAlias {
+			expanded[i] = s.expandAliases(tags)
+		}
+	}
+	return strings.Join(expanded, ",")
+}
+
+// addValidationExtension lists the custom validation rule in the `x-validations` schema extension.
+func addValidationExtension(schema *openapi3.Schema, rule string) {
+	if schema.Extensions == nil {
+		schema.Extensions = map[string]interface{}{}
+	}
+	validations, _ := schema.Extensions[validationsExtension].([]string)
+	schema.Extensions[validationsExtension] = append(validations, rule)
+}
+
+// hasRule reports whether the rules of the field itself contain the one with given name, e.g. "required".
+// Rules following `dive` apply to the items, so they are not checked.
+func hasRule(rules string, name string) bool {
+	for _, rule := range strings.Split(rules, ",") {
+		ruleName, _ := splitRule(rule)
+		if ruleName == "dive" {
+			return false
+		}
+		if ruleName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func splitRule(rule string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(rule), "=", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// setLowerBound sets the minimal length of strings, number of items or properties, or the minimum of numbers.
+// The exclusive bound is expressed as the next integer for lengths and as `exclusiveMinimum` for numbers.
+func setLowerBound(schema *openapi3.Schema, param string, exclusive bool) {
+	switch schema.Type {
+	case openapi3.TypeString, openapi3.TypeArray, openapi3.TypeObject:
+		value, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			return
+		}
+		if exclusive {
+			value++
+		}
+		switch schema.Type {
+		case openapi3.TypeString:
+			schema.MinLength = value
+		case openapi3.TypeArray:
+			schema.MinItems = value
+		default:
+			schema.MinProps = value
+		}
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		if value, err := strconv.ParseFloat(param, 64); err == nil {
+			schema.Min = &value
+			schema.ExclusiveMin = exclusive
+		}
+	}
+}
+
+// setUpperBound sets the maximal length of strings, number of items or properties, or the maximum of numbers.
+// The exclusive bound is expressed as the previous integer for lengths and as `exclusiveMaximum` for numbers.
+func setUpperBound(schema *openapi3.Schema, param string, exclusive bool) {
+	switch schema.Type {
+	case openapi3.TypeString, openapi3.TypeArray, openapi3.TypeObject:
+		value, err := strconv.ParseUint(param, 10, 64)
+		if err != nil || (exclusive && value == 0) {
+			return
+		}
+		if exclusive {
+			value--
+		}
+		switch schema.Type {
+		case openapi3.TypeString:
+			schema.MaxLength = &value
+		case openapi3.TypeArray:
+			schema.MaxItems = &value
+		default:
+			schema.MaxProps = &value
+		}
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		if value, err := strconv.ParseFloat(param, 64); err == nil {
+			schema.Max = &value
+			schema.ExclusiveMax = exclusive
+		}
+	}
+}
@@ -5,10 +5,16 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gin-gonic/gin"
 	"gopkg.in/yaml.v3"
+	"io/fs"
 	"os"
+	"path"
 	"regexp"
 )
 
+var pathParamRegExp = regexp.MustCompile("/[:*]([^/]+)")
+
+// New creates the documentation configured with the options, filling the empty ones with the defaults.
+// It panics if the interactive documentation is enabled and its renderer is unknown or its assets are missing.
 func New(options *Options) *Docs {
 	if options.Title == "" {
 		options.Title = defaultOptions.Title
@@ -28,6 +34,15 @@ func New(options *Options) *Docs {
 	if options.Servers == nil {
 		options.Servers = defaultOptions.Servers
 	}
+	if options.UI == "" {
+		options.UI = defaultOptions.UI
+	}
+	if options.Assets == nil {
+		options.Assets = defaultAssets()
+	}
+	if options.InteractiveUrl != NoUrl && options.JsonUrl != NoUrl {
+		options.UI.validate(options.Assets, options.CDNAssets)
+	}
 
 	servers := openapi3.Servers{}
 	for _, url := range options.Servers {
@@ -60,15 +75,23 @@ func New(options *Options) *Docs {
 		InteractiveUrl: options.InteractiveUrl,
 		JsonUrl:        options.JsonUrl,
 		YamlUrl:        options.YamlUrl,
+		UI:             options.UI,
+		Assets:         options.Assets,
+		CDNAssets:      options.CDNAssets,
 	}
+	d.Schemas = NewSchemas(d.OpenApi)
 	return &d
 }
 
 type Docs struct {
 	OpenApi        *openapi3.T
+	Schemas        *Schemas
 	InteractiveUrl string
 	JsonUrl        string
 	YamlUrl        string
+	UI             UI
+	Assets         fs.FS
+	CDNAssets      bool
 }
 
 func (d *Docs) SetPath(path string, method string, doc *Endpoint) {
@@ -93,9 +116,10 @@ func (d *Docs) SaveAsYaml(path string) error {
 	return os.WriteFile(path, data, 0644)
 }
 
+// NormalizePath converts the Gin path into OpenAPI format,
+// e.g. both named `/:id` and catch-all `/*filepath` parameters become `/{id}` and `/{filepath}`.
 func (d *Docs) NormalizePath(path string) string {
-	reg := regexp.MustCompile("/:([0-9a-zA-Z]+)")
-	return reg.ReplaceAllString(path, "/{${1}}")
+	return pathParamRegExp.ReplaceAllString(path, "/{${1}}")
 }
 
 func (d *Docs) PathItem(path string) *openapi3.PathItem {
@@ -116,6 +140,7 @@ func (d *Docs) RegisterRoutes(router gin.IRouter) {
 		router.GET(d.JsonUrl, handler.JsonFile)
 		if d.InteractiveUrl != NoUrl {
 			router.GET(d.InteractiveUrl, handler.Docs)
+			router.GET(path.Join(d.InteractiveUrl, assetsPath, "*filepath"), handler.Asset)
 		}
 	}
 }
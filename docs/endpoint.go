@@ -1,10 +1,11 @@
 package docs
 
 import (
+	"encoding"
 	"fmt"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gin-gonic/gin/binding"
-	"math"
+	"io"
 	"mime/multipart"
 	"reflect"
 	"strconv"
@@ -12,102 +13,390 @@ import (
 	"time"
 )
 
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	stringType          = reflect.TypeOf("")
+	fileHeaderType      = reflect.TypeOf(multipart.FileHeader{})
+	bytesType           = reflect.TypeOf([]byte{})
+	readerType          = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	binaryResponseType  = reflect.TypeOf((*BinaryResponse)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
 type Endpoint openapi3.Operation
 
+// GnRouteOption makes the endpoint documentation an option of the route, e.g. `r.GET("/shops", getShops, &docs.Endpoint{...})`.
+func (e *Endpoint) GnRouteOption() {}
+
+// WithDefaults returns a copy of the endpoint, where the values it doesn't set are taken from the defaults,
+// e.g. the documentation of the router group. The given values take precedence:
+//   - tags, security requirements, servers and external docs are taken from the defaults only if not set (nil),
+//     so an empty list can be used to clear them,
+//   - summary and description are taken from the defaults if empty,
+//   - the endpoint is deprecated if any of them is deprecated,
+//   - parameters, responses, callbacks and extensions are merged.
+//
+// If the defaults are nil, the endpoint is returned as is.
+func (e *Endpoint) WithDefaults(defaults *Endpoint) *Endpoint {
+	if defaults == nil {
+		return e
+	}
+	if e == nil {
+		e = &Endpoint{}
+	}
+
+	merged := *e
+	if merged.Tags == nil && defaults.Tags != nil {
+		merged.Tags = append([]string{}, defaults.Tags...)
+	}
+	if merged.Summary == "" {
+		merged.Summary = defaults.Summary
+	}
+	if merged.Description == "" {
+		merged.Description = defaults.Description
+	}
+	if merged.Security == nil {
+		merged.Security = defaults.Security
+	}
+	if merged.Servers == nil {
+		merged.Servers = defaults.Servers
+	}
+	if merged.ExternalDocs == nil {
+		merged.ExternalDocs = defaults.ExternalDocs
+	}
+	merged.Deprecated = e.Deprecated || defaults.Deprecated
+
+	merged.Parameters = nil
+	for _, param := range defaults.Parameters {
+		if param.Value == nil || !e.hasParameter(param.Value.Name, param.Value.In) {
+			merged.Parameters = append(merged.Parameters, param)
+		}
+	}
+	merged.Parameters = append(merged.Parameters, e.Parameters...)
+
+	if len(defaults.Responses) > 0 {
+		merged.Responses = make(openapi3.Responses, len(defaults.Responses)+len(e.Responses))
+		for code, response := range defaults.Responses {
+			merged.Responses[code] = response
+		}
+		for code, response := range e.Responses {
+			merged.Responses[code] = response
+		}
+	}
+	if len(defaults.Callbacks) > 0 {
+		merged.Callbacks = make(openapi3.Callbacks, len(defaults.Callbacks)+len(e.Callbacks))
+		for name, callback := range defaults.Callbacks {
+			merged.Callbacks[name] = callback
+		}
+		for name, callback := range e.Callbacks {
+			merged.Callbacks[name] = callback
+		}
+	}
+	if len(defaults.Extensions) > 0 {
+		merged.Extensions = make(map[string]interface{}, len(defaults.Extensions)+len(e.Extensions))
+		for name, value := range defaults.Extensions {
+			merged.Extensions[name] = value
+		}
+		for name, value := range e.Extensions {
+			merged.Extensions[name] = value
+		}
+	}
+	return &merged
+}
+
 func (e *Endpoint) SetTagsFromPath(path string) {
 	if e.Tags != nil {
 		return
 	}
 	pathNormalWords := strings.Split(path, "/")
 	for _, word := range pathNormalWords {
-		if !strings.Contains(word, ":") && word != "" {
+		if !strings.ContainsAny(word, ":*") && word != "" {
 			e.Tags = append(e.Tags, word)
 		}
 	}
 }
 
-func (e *Endpoint) SetBodyType(bodyType reflect.Type) {
+// SetBodyType documents the request body. If no content types are given, the body is documented as JSON.
+// Form content types use `form` tags as property names.
+func (e *Endpoint) SetBodyType(schemas *Schemas, bodyType reflect.Type, contentTypes ...string) {
+	if len(contentTypes) == 0 {
+		contentTypes = []string{binding.MIMEJSON}
+	}
+
+	content := openapi3.NewContent()
+	for _, contentType := range contentTypes {
+		switch contentType {
+		case binding.MIMEPOSTForm, binding.MIMEMultipartPOSTForm:
+			content[contentType] = openapi3.NewMediaType().WithSchema(schemas.formToSchema(bodyType))
+		default:
+			content[contentType] = openapi3.NewMediaType().WithSchemaRef(schemas.typeToSchemaRef(bodyType))
+		}
+	}
+
 	e.RequestBody = &openapi3.RequestBodyRef{
 		Value: &openapi3.RequestBody{
 			Required: true,
-			Content:  openapi3.NewContentWithSchema(typeToSchema(bodyType), []string{binding.MIMEJSON}),
+			Content:  content,
 		},
 	}
 }
 
-func (e *Endpoint) AddResponse(responseType reflect.Type) {
-	e.addResponse(responseType, 200)
+// AddResponse documents the response of given type. If no content types are given, the response is documented as JSON.
+func (e *Endpoint) AddResponse(schemas *Schemas, responseType reflect.Type, contentTypes ...string) {
+	e.addResponse(schemas, responseType, 200, contentTypes)
 }
 
-func (e *Endpoint) AddErrorResponse(responseType reflect.Type) {
-	e.addResponse(responseType, 500)
+// AddErrorResponse documents the error response of given type. If no content types are given, the response is documented as JSON.
+func (e *Endpoint) AddErrorResponse(schemas *Schemas, responseType reflect.Type, contentTypes ...string) {
+	e.addResponse(schemas, responseType, 500, contentTypes)
 }
 
-func (e *Endpoint) addResponse(responseType reflect.Type, defaultStatus int) {
+// AddStreamResponse documents a streamed response with the given status, where each sent value is of the given type.
+func (e *Endpoint) AddStreamResponse(schemas *Schemas, itemType reflect.Type, status int, contentTypes ...string) {
 	if len(e.Responses) == 0 {
 		e.Responses = make(openapi3.Responses, 1)
 	}
 
-	schema := typeToSchema(responseType)
+	e.Responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
+		Value: &openapi3.Response{Content: openapi3.NewContentWithSchemaRef(schemas.typeToSchemaRef(itemType), contentTypes)},
+	}
+}
+
+func (e *Endpoint) addResponse(schemas *Schemas, responseType reflect.Type, defaultStatus int, contentTypes []string) {
+	if len(e.Responses) == 0 {
+		e.Responses = make(openapi3.Responses, 1)
+	}
+	if len(contentTypes) == 0 {
+		contentTypes = []string{binding.MIMEJSON}
+	}
+
+	schema := schemas.responseSchema(responseType)
 	response := &openapi3.ResponseRef{
-		Value: &openapi3.Response{Content: openapi3.NewContentWithJSONSchema(schema)},
+		Value: &openapi3.Response{Content: openapi3.NewContentWithSchemaRef(schema, contentTypes)},
 	}
 
-	statusCode := strconv.Itoa(DefaultStatus(responseType, defaultStatus))
+	for _, code := range responseCodes(responseType, defaultStatus) {
+		e.Responses[code] = response
+	}
+}
+
+// AddResponseHeadersType documents all fields of the struct having the `header` tag as response headers.
+// The headers are added to the responses listed in the `status_codes` tag of the struct,
+// otherwise to the responses of given type, or the response with the default status if the type is nil.
+func (e *Endpoint) AddResponseHeadersType(schemas *Schemas, headersType reflect.Type, responseType reflect.Type, defaultStatus int) {
+	headersType = directType(headersType)
 
-	codes := append(getStatusCodes(responseType), statusCode)
+	codes := getStatusCodes(headersType)
+	if len(codes) == 0 && responseType != nil {
+		codes = responseCodes(responseType, defaultStatus)
+	} else if len(codes) == 0 {
+		codes = []string{strconv.Itoa(defaultStatus)}
+	}
+
+	headers := openapi3.Headers{}
+	for i := 0; i < headersType.NumField(); i++ {
+		field := headersType.Field(i)
+		name := field.Tag.Get(headerTag)
+		if name == "" {
+			continue
+		}
+
+		header := &openapi3.Header{Parameter: openapi3.Parameter{
+			Required: field.Type.Kind() != reflect.Ptr && field.Type.Kind() != reflect.Slice,
+			Schema:   openapi3.NewSchemaRef("", schemas.paramSchema(field.Type)),
+		}}
+		applyParamAnnotations(&header.Parameter, field)
+		headers[name] = &openapi3.HeaderRef{Value: header}
+	}
+
+	if len(e.Responses) == 0 {
+		e.Responses = make(openapi3.Responses, len(codes))
+	}
 	for _, code := range codes {
-		e.Responses[code] = response
+		// the response may be shared by many status codes, so it is copied before adding the headers
+		response := &openapi3.Response{}
+		if existing := e.Responses[code]; existing != nil && existing.Value != nil {
+			*response = *existing.Value
+		}
+		responseHeaders := make(openapi3.Headers, len(response.Headers)+len(headers))
+		for name, header := range response.Headers {
+			responseHeaders[name] = header
+		}
+		for name, header := range headers {
+			responseHeaders[name] = header
+		}
+		response.Headers = responseHeaders
+		e.Responses[code] = &openapi3.ResponseRef{Value: response}
 	}
 }
 
-func (e *Endpoint) SetQueryType(queryType reflect.Type) {
-	queryType = directType(queryType)
-
-	for i := 0; i < queryType.NumField(); i++ {
-		if name := queryType.Field(i).Tag.Get("form"); name != "" {
-			e.Parameters = append(e.Parameters, &openapi3.ParameterRef{
-				Value: &openapi3.Parameter{
-					Name: name,
-					In:   "query",
-				},
-			})
+// responseCodes returns the status codes of the response of given type: the default one and the ones from the `status_codes` tag.
+func responseCodes(responseType reflect.Type, defaultStatus int) []string {
+	statusCode := strconv.Itoa(DefaultStatus(responseType, defaultStatus))
+	return append(getStatusCodes(responseType), statusCode)
+}
+
+// SetQueryType documents the fields of the query struct as query parameters, with their schemas, constraints and defaults.
+// Slices are documented as arrays sent by repeating the parameter, e.g. `?tag=a&tag=b`.
+// Structs and maps are documented as deep objects, e.g. `?filter[status]=new`.
+func (e *Endpoint) SetQueryType(schemas *Schemas, queryType reflect.Type) {
+	for _, field := range formFields(directType(queryType)) {
+		if e.hasParameter(field.name, queryTag) {
+			continue
+		}
+
+		rules := schemas.expandAliases(field.Tag.Get(bindingTag))
+		fieldType := directType(field.Type)
+		deepObject := fieldType.Kind() == reflect.Map || isFormObject(fieldType)
+
+		var schema *openapi3.Schema
+		if deepObject {
+			schema = schemas.formToSchema(fieldType)
+		} else {
+			schema = schemas.paramSchema(field.Type)
 		}
+		schemas.applyBindingConstraints(schema, rules)
+		if field.hasDefault {
+			schema.Default = parseDefaultValue(field.defaultValue, field.Type)
+		}
+
+		param := &openapi3.Parameter{
+			Name:     field.name,
+			In:       queryTag,
+			Required: hasRule(rules, "required"),
+			Schema:   openapi3.NewSchemaRef("", schema),
+		}
+		if deepObject {
+			explode := true
+			param.Style = openapi3.SerializationDeepObject
+			param.Explode = &explode
+		} else if schema.Type == openapi3.TypeArray {
+			explode := true
+			param.Style = openapi3.SerializationForm
+			param.Explode = &explode
+		}
+		applyParamAnnotations(param, field.StructField)
+		e.Parameters = append(e.Parameters, &openapi3.ParameterRef{Value: param})
 	}
 }
 
-func (e *Endpoint) AddPathParam(name string, type_ reflect.Type) {
+func (e *Endpoint) AddPathParam(schemas *Schemas, name string, type_ reflect.Type) {
+	if e.hasParameter(name, pathTag) {
+		return
+	}
 	e.Parameters = append(e.Parameters, &openapi3.ParameterRef{
 		Value: &openapi3.Parameter{
 			Name:     name,
-			In:       "path",
+			In:       pathTag,
 			Required: true,
-			Schema: &openapi3.SchemaRef{
-				Value: &openapi3.Schema{
-					Type: typeAsString(type_),
-				},
-			},
+			Schema:   openapi3.NewSchemaRef("", schemas.paramSchema(type_)),
 		},
 	})
 }
 
-func (e *Endpoint) AddHeadersType(headerType reflect.Type) {
+// AddPathType documents all fields of the struct having the `uri` tag as path parameters.
+func (e *Endpoint) AddPathType(schemas *Schemas, pathType reflect.Type) {
+	pathType = directType(pathType)
+
+	for i := 0; i < pathType.NumField(); i++ {
+		field := pathType.Field(i)
+		name := field.Tag.Get(uriTag)
+		if name == "" || e.hasParameter(name, pathTag) {
+			continue
+		}
+
+		schema := schemas.paramSchema(field.Type)
+		schemas.applyBindingConstraints(schema, field.Tag.Get(bindingTag))
+
+		param := &openapi3.Parameter{
+			Name:     name,
+			In:       pathTag,
+			Required: true,
+			Schema:   openapi3.NewSchemaRef("", schema),
+		}
+		applyParamAnnotations(param, field)
+		e.Parameters = append(e.Parameters, &openapi3.ParameterRef{Value: param})
+	}
+}
+
+func (e *Endpoint) hasParameter(name string, in string) bool {
+	for _, param := range e.Parameters {
+		if param.Value != nil && param.Value.Name == name && param.Value.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+// AddHeadersType documents all fields of the struct having the `header` tag as header parameters,
+// with their schemas, constraints and the defaults from the `default` option of the tag.
+func (e *Endpoint) AddHeadersType(schemas *Schemas, headerType reflect.Type) {
 	headerType = directType(headerType)
 
 	for i := 0; i < headerType.NumField(); i++ {
-		tag := headerType.Field(i).Tag
-		if name := tag.Get("header"); name != "" {
-			e.Parameters = append(e.Parameters, &openapi3.ParameterRef{
-				Value: &openapi3.Parameter{
-					Name:     name,
-					In:       headerTag,
-					Required: strings.Contains(tag.Get(bindingTag), "required"),
-				},
-			})
+		field := headerType.Field(i)
+		name, options := parseTag(field.Tag.Get(headerTag))
+		if name == "" || e.hasParameter(name, headerTag) {
+			continue
+		}
+
+		rules := schemas.expandAliases(field.Tag.Get(bindingTag))
+		schema := schemas.paramSchema(field.Type)
+		schemas.applyBindingConstraints(schema, rules)
+		for _, option := range options {
+			if key, value := splitRule(option); key == defaultTag {
+				schema.Default = parseDefaultValue(value, field.Type)
+			}
 		}
+
+		param := &openapi3.Parameter{
+			Name:     name,
+			In:       headerTag,
+			Required: hasRule(rules, "required"),
+			Schema:   openapi3.NewSchemaRef("", schema),
+		}
+		applyParamAnnotations(param, field)
+		e.Parameters = append(e.Parameters, &openapi3.ParameterRef{Value: param})
 	}
 }
 
+// AddCookiesType documents all fields of the struct having the `cookie` tag as cookie parameters.
+func (e *Endpoint) AddCookiesType(schemas *Schemas, cookiesType reflect.Type) {
+	cookiesType = directType(cookiesType)
+
+	for i := 0; i < cookiesType.NumField(); i++ {
+		field := cookiesType.Field(i)
+		name, options := parseTag(field.Tag.Get(cookieTag))
+		if name == "" || e.hasParameter(name, cookieTag) {
+			continue
+		}
+
+		rules := schemas.expandAliases(field.Tag.Get(bindingTag))
+		schema := schemas.paramSchema(field.Type)
+		schemas.applyBindingConstraints(schema, rules)
+		for _, option := range options {
+			if key, value := splitRule(option); key == defaultTag {
+				schema.Default = parseDefaultValue(value, field.Type)
+			}
+		}
+
+		param := &openapi3.Parameter{
+			Name:     name,
+			In:       cookieTag,
+			Required: hasRule(rules, "required"),
+			Schema:   openapi3.NewSchemaRef("", schema),
+		}
+		applyParamAnnotations(param, field)
+		e.Parameters = append(e.Parameters, &openapi3.ParameterRef{Value: param})
+	}
+}
+
+// BinaryResponse is implemented by the types sent as raw binary data, e.g. file downloads.
+// Such responses, as well as []byte and io.Reader, are documented with the binary schema.
+type BinaryResponse interface {
+	GnBinaryResponse()
+}
+
 func DefaultStatus(type_ reflect.Type, default_ ...int) int {
 	if type_.Kind() == reflect.Ptr {
 		type_ = type_.Elem()
@@ -131,121 +420,6 @@ func DefaultStatus(type_ reflect.Type, default_ ...int) int {
 	return 200
 }
 
-func typeToSchema(type_ reflect.Type) *openapi3.Schema {
-	type_ = directType(type_)
-
-	model := reflect.New(type_).Elem().Interface()
-	switch model.(type) {
-	case int:
-		return openapi3.NewIntegerSchema().WithMin(math.MinInt).WithMax(math.MaxInt)
-	case int8:
-		return openapi3.NewIntegerSchema().WithMin(math.MinInt8).WithMax(math.MaxInt8)
-	case int16:
-		return openapi3.NewIntegerSchema().WithMin(math.MinInt16).WithMax(math.MaxInt16)
-	case int32:
-		return openapi3.NewInt32Schema().WithMin(math.MinInt32).WithMax(math.MaxInt32)
-	case int64:
-		return openapi3.NewInt64Schema().WithMin(math.MinInt64).WithMax(math.MaxInt64)
-	case uint:
-		return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint)
-	case uint8:
-		return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint8)
-	case uint16:
-		return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint16)
-	case uint32:
-		return openapi3.NewInt32Schema().WithMin(0).WithMax(math.MaxUint32)
-	case uint64:
-		return openapi3.NewInt64Schema().WithMin(0).WithMax(math.MaxUint64)
-	case string:
-		return openapi3.NewStringSchema()
-	case time.Time:
-		return openapi3.NewDateTimeSchema()
-	case float32, float64:
-		return openapi3.NewFloat64Schema()
-	case bool:
-		return openapi3.NewBoolSchema()
-	case []byte:
-		return openapi3.NewBytesSchema()
-	case *multipart.FileHeader:
-		return openapi3.NewStringSchema().WithFormat("binary")
-	case []*multipart.FileHeader:
-		return openapi3.NewArraySchema().WithItems(&openapi3.Schema{
-			Type:   "string",
-			Format: "binary",
-		})
-	default:
-		switch type_.Kind() {
-		case reflect.Int:
-			return openapi3.NewIntegerSchema().WithMin(math.MinInt).WithMax(math.MaxInt)
-		case reflect.Int8:
-			return openapi3.NewIntegerSchema().WithMin(math.MinInt8).WithMax(math.MaxInt8)
-		case reflect.Int16:
-			return openapi3.NewIntegerSchema().WithMin(math.MinInt16).WithMax(math.MaxInt16)
-		case reflect.Int32:
-			return openapi3.NewInt32Schema().WithMin(math.MinInt32).WithMax(math.MaxInt32)
-		case reflect.Int64:
-			return openapi3.NewInt64Schema().WithMin(math.MinInt64).WithMax(math.MaxInt64)
-		case reflect.Uint:
-			return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint)
-		case reflect.Uint8:
-			return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint8)
-		case reflect.Uint16:
-			return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint16)
-		case reflect.Uint32:
-			return openapi3.NewInt32Schema().WithMin(0).WithMax(math.MaxUint32)
-		case reflect.Uint64:
-			return openapi3.NewInt64Schema().WithMin(0).WithMax(math.MaxUint64)
-		case reflect.Float32, reflect.Float64:
-			return openapi3.NewFloat64Schema()
-		case reflect.Struct:
-			return structToSchema(type_)
-		case reflect.Slice, reflect.Array:
-			return openapi3.NewArraySchema().WithItems(typeToSchema(type_.Elem()))
-		case reflect.Map:
-			return openapi3.NewObjectSchema().WithAdditionalProperties(typeToSchema(type_.Elem()))
-		case reflect.Interface:
-			return openapi3.NewSchema().WithDefault("any")
-		case reflect.String:
-			return openapi3.NewStringSchema()
-		case reflect.Bool:
-			return openapi3.NewBoolSchema()
-		default:
-			panic(fmt.Sprintf("not allowed type or kind: %s(%s)", type_, type_.Kind()))
-		}
-	}
-}
-
-func structToSchema(type_ reflect.Type) *openapi3.Schema {
-	schema := openapi3.NewObjectSchema()
-	for i := 0; i < type_.NumField(); i++ {
-		field := type_.Field(i)
-		tags := field.Tag
-		fieldSchema := typeToSchema(field.Type)
-
-		fieldName, exists := tags.Lookup(jsonTag)
-		if !exists || fieldName == "-" {
-			continue
-		}
-
-		schema.Properties[fieldName] = openapi3.NewSchemaRef("", fieldSchema)
-
-		bindingTag, exists := tags.Lookup(bindingTag)
-		if exists {
-			for _, validation := range strings.Split(bindingTag, ",") {
-				if validation == "required" {
-					schema.Required = append(schema.Required, fieldName)
-				}
-			}
-		}
-
-		defaultValue, exists := tags.Lookup(defaultTag)
-		if exists {
-			fieldSchema.Default = defaultValue
-		}
-	}
-	return schema
-}
-
 func getStatusCodes(type_ reflect.Type) []string {
 	var codes []string
 	type_ = directType(type_)
@@ -261,13 +435,21 @@ func getStatusCodes(type_ reflect.Type) []string {
 }
 
 func typeAsString(t reflect.Type) string {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return "string"
+	}
 	switch t.Kind() {
-	case reflect.Int:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
 	case reflect.String:
 		return "string"
 	default:
-		panic(fmt.Sprintf("unknown type: %s in path params, must be integer or string", t))
+		panic(fmt.Sprintf("unknown type: %s in path params, must be a scalar or implement encoding.TextUnmarshaler", t))
 	}
 }
 
@@ -0,0 +1,209 @@
+package docs
+
+import (
+	"reflect"
+	"strings"
+)
+
+// structField is a field of the struct as seen by encoding/json, with fields of embedded structs promoted.
+type structField struct {
+	reflect.StructField
+	name     string
+	depth    int
+	tagged   bool
+	asString bool
+}
+
+// structFields returns the fields of the struct, named after the given tag, following the encoding/json rules:
+//   - unexported fields and fields tagged with "-" are skipped,
+//   - untagged fields are named after the Go field,
+//   - fields of untagged embedded structs are promoted to the parent,
+//   - if there are fields with the same name, the least nested one wins, and the tagged one when equally nested.
+//     If there is still more than one, none of them is used.
+func structFields(type_ reflect.Type, nameTag string) []structField {
+	var fields []structField
+	collectFields(type_, nameTag, 0, map[reflect.Type]bool{}, &fields)
+
+	byName := map[string][]structField{}
+	var names []string
+	for _, field := range fields {
+		if _, exists := byName[field.name]; !exists {
+			names = append(names, field.name)
+		}
+		byName[field.name] = append(byName[field.name], field)
+	}
+
+	var result []structField
+	for _, name := range names {
+		if field, ok := dominantField(byName[name]); ok {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+func collectFields(type_ reflect.Type, nameTag string, depth int, visited map[reflect.Type]bool, fields *[]structField) {
+	if visited[type_] {
+		return
+	}
+	visited[type_] = true
+
+	for i := 0; i < type_.NumField(); i++ {
+		field := type_.Field(i)
+		fieldType := directType(field.Type)
+		exported := field.PkgPath == ""
+
+		if !exported && !(field.Anonymous && fieldType.Kind() == reflect.Struct) {
+			continue
+		}
+
+		tag := field.Tag.Get(nameTag)
+		if tag == "-" {
+			continue
+		}
+		name, options := parseTag(tag)
+
+		if name == "" && field.Anonymous && fieldType.Kind() == reflect.Struct {
+			collectFields(fieldType, nameTag, depth+1, visited, fields)
+			continue
+		}
+		if !exported || !isEncodable(fieldType) {
+			continue
+		}
+
+		*fields = append(*fields, structField{
+			StructField: field,
+			name:        firstNonEmpty(name, field.Name),
+			depth:       depth,
+			tagged:      name != "",
+			asString:    nameTag == jsonTag && options.contains("string") && isScalar(fieldType),
+		})
+	}
+}
+
+// dominantField chooses the field used among the fields with the same name.
+func dominantField(fields []structField) (structField, bool) {
+	minDepth := fields[0].depth
+	for _, field := range fields {
+		if field.depth < minDepth {
+			minDepth = field.depth
+		}
+	}
+
+	var candidates []structField
+	for _, field := range fields {
+		if field.depth == minDepth {
+			candidates = append(candidates, field)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+
+	var tagged []structField
+	for _, field := range candidates {
+		if field.tagged {
+			tagged = append(tagged, field)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return structField{}, false
+}
+
+type tagOptions []string
+
+func (o tagOptions) contains(option string) bool {
+	for _, o := range o {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// isEncodable reports whether values of the type can be encoded, e.g. functions and channels can not.
+func isEncodable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return false
+	default:
+		return true
+	}
+}
+
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// formField is a field bound from the query or form values.
+type formField struct {
+	reflect.StructField
+	name         string
+	defaultValue string
+	hasDefault   bool
+}
+
+// formFields returns the fields bound from the query or form values, following the Gin rules:
+//   - fields are named after the `form` tag, or the Go field name if there is no tag,
+//   - unexported fields and fields tagged with "-" are skipped,
+//   - fields of embedded structs and untagged struct fields are flattened,
+//   - the default value may be given with the `default` option, e.g. `form:"page,default=1"`.
+func formFields(type_ reflect.Type) []formField {
+	var fields []formField
+	for i := 0; i < type_.NumField(); i++ {
+		field := type_.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get(formTag)
+		if tag == "-" {
+			continue
+		}
+		name, options := parseTag(tag)
+
+		fieldType := directType(field.Type)
+		if fieldType.Kind() == reflect.Struct && fieldType != timeType && (field.Anonymous || name == "") {
+			fields = append(fields, formFields(fieldType)...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		formField := formField{StructField: field, name: firstNonEmpty(name, field.Name)}
+		for _, option := range options {
+			if key, value := splitRule(option); key == defaultTag {
+				formField.defaultValue, formField.hasDefault = value, true
+			}
+		}
+		if value, exists := field.Tag.Lookup(defaultTag); exists {
+			formField.defaultValue, formField.hasDefault = value, true
+		}
+		fields = append(fields, formField)
+	}
+	return fields
+}
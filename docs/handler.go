@@ -1,25 +1,45 @@
 package docs
 
 import (
-	_ "embed"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"gopkg.in/yaml.v3"
 	"html/template"
 	"net/http"
+	"path"
+	"strings"
 )
 
-//go:embed swagger_template.html
-var docsTemplateStr string
-
 type Handler struct {
 	docs     *Docs
 	template *template.Template
 }
 
+// NewHandler creates the handler of the documentation routes.
 func NewHandler(docs *Docs) *Handler {
-	tmpl := template.Must(template.New("").Parse(docsTemplateStr))
-	return &Handler{docs: docs, template: tmpl}
+	handler := &Handler{docs: docs}
+	handler.template = template.Must(template.New("").
+		Funcs(template.FuncMap{"asset": handler.assetUrl}).
+		Parse(docs.UI.template()))
+	return handler
+}
+
+// assetUrl returns the url of the asset served by the Asset handler, or its CDN url if CDNAssets is enabled.
+func (h *Handler) assetUrl(name string) string {
+	if h.docs.CDNAssets {
+		return manifest[name].Url
+	}
+	return path.Join(h.docs.InteractiveUrl, assetsPath, name)
+}
+
+// Asset serves the scripts and styles of the interactive documentation.
+func (h *Handler) Asset(ctx *gin.Context) {
+	name := strings.TrimPrefix(ctx.Param("filepath"), "/")
+	if _, listed := manifest[name]; !listed {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	ctx.FileFromFS(name, http.FS(h.docs.Assets))
 }
 
 func (h *Handler) Docs(ctx *gin.Context) {
@@ -0,0 +1,148 @@
+// Command fetchassets downloads the scripts and styles of the interactive documentation renderers, with their
+// licenses, listed in the manifest.json of the given assets directory, so they can be embedded in the docs package.
+//
+// The downloads are verified with the sha256 checksums pinned in the manifest. Missing checksums are computed
+// and saved in the manifest. With the -check flag, the files already in the directory are verified instead,
+// without downloading anything.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const manifestFile = "manifest.json"
+
+type asset struct {
+	Url     string `json:"url"`
+	Sha256  string `json:"sha256,omitempty"`
+	License bool   `json:"license,omitempty"`
+}
+
+func main() {
+	check := flag.Bool("check", false, "verify the checksums of the assets in the directory instead of downloading them")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal("usage: fetchassets [-check] <assets directory>")
+	}
+	dir := flag.Arg(0)
+
+	manifest, err := readManifest(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	names := make([]string, 0, len(manifest))
+	for name := range manifest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if *check {
+		for _, name := range names {
+			if err = verify(manifest[name], filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+				log.Fatalf("invalid asset %s: %v", name, err)
+			}
+		}
+		log.Printf("verified %d assets", len(names))
+		return
+	}
+
+	pinned := false
+	for _, name := range names {
+		entry := manifest[name]
+		checksum, err := download(entry, filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			log.Fatalf("cannot download %s: %v", name, err)
+		}
+		if entry.Sha256 == "" {
+			entry.Sha256 = checksum
+			manifest[name] = entry
+			pinned = true
+			log.Printf("downloaded %s, pinned checksum %s", name, checksum)
+		} else {
+			log.Printf("downloaded %s", name)
+		}
+	}
+
+	if pinned {
+		if err = writeManifest(dir, manifest); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func readManifest(dir string) (map[string]asset, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+	manifest := map[string]asset{}
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func writeManifest(dir string, manifest map[string]asset) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, manifestFile), append(data, '\n'), 0644)
+}
+
+// download saves the asset under the path, if its checksum matches the pinned one, and returns the checksum.
+func download(entry asset, path string) (string, error) {
+	response, err := http.Get(entry.Url)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status: %s", response.Status)
+	}
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	checksum := sha256Hex(content)
+	if entry.Sha256 != "" && checksum != entry.Sha256 {
+		return "", fmt.Errorf("checksum mismatch: expected %s, got %s", entry.Sha256, checksum)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return checksum, os.WriteFile(path, content, 0644)
+}
+
+// verify checks that the asset file exists and matches the pinned checksum.
+func verify(entry asset, path string) error {
+	if entry.Sha256 == "" {
+		return fmt.Errorf("checksum is not pinned")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if checksum := sha256Hex(content); checksum != entry.Sha256 {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", entry.Sha256, checksum)
+	}
+	return nil
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
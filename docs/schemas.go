@@ -0,0 +1,303 @@
+package docs
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"math"
+	"mime/multipart"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const componentsPrefix = "#/components/schemas/"
+
+var (
+	packageQualifierRegExp = regexp.MustCompile(`(?:[\w\-~.]+/)*[\w\-~]+\.`)
+	nonIdentifierRegExp    = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+)
+
+// ValidationSchema documents the custom validation in the schema of the validated value.
+// It gets the parameter of the validation tag, e.g. "5" for `binding:"divisible=5"`.
+type ValidationSchema func(schema *openapi3.Schema, param string)
+
+// Schemas generates the OpenAPI schemas of Go types.
+// Named structs are registered as components of the spec and referenced with `$ref`.
+// It knows the custom validations and aliases registered in the router validator, so they are documented as well.
+type Schemas struct {
+	spec        *openapi3.T
+	components  map[reflect.Type]string
+	validations map[string]ValidationSchema
+	aliases     map[string]string
+}
+
+func NewSchemas(spec *openapi3.T) *Schemas {
+	return &Schemas{
+		spec:        spec,
+		components:  map[reflect.Type]string{},
+		validations: map[string]ValidationSchema{},
+		aliases:     map[string]string{},
+	}
+}
+
+// RegisterValidation makes the custom validation tag visible in the schemas.
+// The tag is listed in the `x-validations` extension and, if given, the document function adds the constraints it represents.
+func (s *Schemas) RegisterValidation(tag string, document ValidationSchema) {
+	s.validations[tag] = document
+}
+
+// RegisterAlias registers the validation alias, so the tags it stands for are documented.
+func (s *Schemas) RegisterAlias(alias string, tags string) {
+	s.aliases[alias] = tags
+}
+
+// typeToSchemaRef returns the reference to the component schema for named structs,
+// and the inline schema for other types.
+func (s *Schemas) typeToSchemaRef(type_ reflect.Type) *openapi3.SchemaRef {
+	type_ = directType(type_)
+	if !isComponent(type_) {
+		schema := s.typeToSchema(type_)
+		applyTypeAnnotations(schema, type_)
+		return openapi3.NewSchemaRef("", schema)
+	}
+
+	name, registered := s.components[type_]
+	if !registered {
+		name = s.componentName(type_)
+		s.components[type_] = name
+
+		// the component is registered before its properties are generated, so self-referencing types end with `$ref`
+		schema := &openapi3.Schema{}
+		s.componentSchemas()[name] = openapi3.NewSchemaRef("", schema)
+		*schema = *s.structToSchema(type_)
+		applyTypeAnnotations(schema, type_)
+	}
+	return openapi3.NewSchemaRef(componentsPrefix+name, s.componentSchemas()[name].Value)
+}
+
+func (s *Schemas) componentSchemas() openapi3.Schemas {
+	if s.spec.Components == nil {
+		s.spec.Components = &openapi3.Components{}
+	}
+	if s.spec.Components.Schemas == nil {
+		s.spec.Components.Schemas = openapi3.Schemas{}
+	}
+	return s.spec.Components.Schemas
+}
+
+// componentName returns a unique name of the component. The name of the type is used, without package qualifiers
+// of generic type arguments. If it is taken, e.g. by a type from other package, it is prefixed with the package name.
+// If it is still taken, a number is appended.
+func (s *Schemas) componentName(type_ reflect.Type) string {
+	name := typeName(type_.Name())
+	if !s.componentExists(name) {
+		return name
+	}
+
+	packagePath := strings.Split(type_.PkgPath(), "/")
+	name = typeName(packagePath[len(packagePath)-1]) + "_" + name
+	if !s.componentExists(name) {
+		return name
+	}
+
+	for i := 2; ; i++ {
+		if numbered := fmt.Sprintf("%s%d", name, i); !s.componentExists(numbered) {
+			return numbered
+		}
+	}
+}
+
+func (s *Schemas) componentExists(name string) bool {
+	_, exists := s.componentSchemas()[name]
+	return exists
+}
+
+// typeName converts the Go type name to the component name, e.g. `Page[github.com/org/models.User]` to `Page_User`.
+func typeName(name string) string {
+	name = packageQualifierRegExp.ReplaceAllString(name, "")
+	return strings.Trim(nonIdentifierRegExp.ReplaceAllString(name, "_"), "_")
+}
+
+// isComponent reports whether the type is documented as a component.
+// Anonymous structs have no name, so they are inlined, as well as the structs having a dedicated schema.
+func isComponent(type_ reflect.Type) bool {
+	return type_.Kind() == reflect.Struct && type_.Name() != "" && type_ != timeType && type_ != fileHeaderType
+}
+
+func (s *Schemas) typeToSchema(type_ reflect.Type) *openapi3.Schema {
+	type_ = directType(type_)
+	if type_ == fileHeaderType {
+		return openapi3.NewStringSchema().WithFormat("binary")
+	}
+
+	model := reflect.New(type_).Elem().Interface()
+	switch model.(type) {
+	case int:
+		return openapi3.NewIntegerSchema().WithMin(math.MinInt).WithMax(math.MaxInt)
+	case int8:
+		return openapi3.NewIntegerSchema().WithMin(math.MinInt8).WithMax(math.MaxInt8)
+	case int16:
+		return openapi3.NewIntegerSchema().WithMin(math.MinInt16).WithMax(math.MaxInt16)
+	case int32:
+		return openapi3.NewInt32Schema().WithMin(math.MinInt32).WithMax(math.MaxInt32)
+	case int64:
+		return openapi3.NewInt64Schema().WithMin(math.MinInt64).WithMax(math.MaxInt64)
+	case uint:
+		return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint)
+	case uint8:
+		return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint8)
+	case uint16:
+		return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint16)
+	case uint32:
+		return openapi3.NewInt32Schema().WithMin(0).WithMax(math.MaxUint32)
+	case uint64:
+		return openapi3.NewInt64Schema().WithMin(0).WithMax(math.MaxUint64)
+	case string:
+		return openapi3.NewStringSchema()
+	case time.Time:
+		return openapi3.NewDateTimeSchema()
+	case float32, float64:
+		return openapi3.NewFloat64Schema()
+	case bool:
+		return openapi3.NewBoolSchema()
+	case []byte:
+		return openapi3.NewBytesSchema()
+	case []*multipart.FileHeader:
+		return openapi3.NewArraySchema().WithItems(&openapi3.Schema{
+			Type:   "string",
+			Format: "binary",
+		})
+	default:
+		switch type_.Kind() {
+		case reflect.Int:
+			return openapi3.NewIntegerSchema().WithMin(math.MinInt).WithMax(math.MaxInt)
+		case reflect.Int8:
+			return openapi3.NewIntegerSchema().WithMin(math.MinInt8).WithMax(math.MaxInt8)
+		case reflect.Int16:
+			return openapi3.NewIntegerSchema().WithMin(math.MinInt16).WithMax(math.MaxInt16)
+		case reflect.Int32:
+			return openapi3.NewInt32Schema().WithMin(math.MinInt32).WithMax(math.MaxInt32)
+		case reflect.Int64:
+			return openapi3.NewInt64Schema().WithMin(math.MinInt64).WithMax(math.MaxInt64)
+		case reflect.Uint:
+			return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint)
+		case reflect.Uint8:
+			return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint8)
+		case reflect.Uint16:
+			return openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint16)
+		case reflect.Uint32:
+			return openapi3.NewInt32Schema().WithMin(0).WithMax(math.MaxUint32)
+		case reflect.Uint64:
+			return openapi3.NewInt64Schema().WithMin(0).WithMax(math.MaxUint64)
+		case reflect.Float32, reflect.Float64:
+			return openapi3.NewFloat64Schema()
+		case reflect.Struct:
+			return s.structToSchema(type_)
+		case reflect.Slice, reflect.Array:
+			schema := openapi3.NewArraySchema()
+			schema.Items = s.typeToSchemaRef(type_.Elem())
+			return schema
+		case reflect.Map:
+			schema := openapi3.NewObjectSchema()
+			schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: s.typeToSchemaRef(type_.Elem())}
+			return schema
+		case reflect.Interface:
+			return openapi3.NewSchema().WithDefault("any")
+		case reflect.String:
+			return openapi3.NewStringSchema()
+		case reflect.Bool:
+			return openapi3.NewBoolSchema()
+		default:
+			panic(fmt.Sprintf("not allowed type or kind: %s(%s)", type_, type_.Kind()))
+		}
+	}
+}
+
+func (s *Schemas) structToSchema(type_ reflect.Type) *openapi3.Schema {
+	return s.structToSchemaWithTag(type_, jsonTag)
+}
+
+// formToSchema returns a schema of a form body, where property names come from `form` tags.
+func (s *Schemas) formToSchema(type_ reflect.Type) *openapi3.Schema {
+	type_ = directType(type_)
+	if type_.Kind() != reflect.Struct {
+		return s.typeToSchema(type_)
+	}
+	return s.structToSchemaWithTag(type_, formTag)
+}
+
+// isFormObject reports whether the form field of given type is a nested struct, not parsed from a single value.
+func isFormObject(t reflect.Type) bool {
+	t = directType(t)
+	return t.Kind() == reflect.Struct && t != timeType && t != fileHeaderType && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// structToSchemaWithTag returns the object schema with the properties named after the given tag.
+// Fields are resolved as in encoding/json, see structFields.
+func (s *Schemas) structToSchemaWithTag(type_ reflect.Type, nameTag string) *openapi3.Schema {
+	schema := openapi3.NewObjectSchema()
+	for _, field := range structFields(type_, nameTag) {
+		tags := field.Tag
+
+		var fieldSchema *openapi3.SchemaRef
+		exampleType := field.Type
+		if field.asString {
+			fieldSchema = openapi3.NewSchemaRef("", openapi3.NewStringSchema())
+			exampleType = stringType
+		} else if nameTag == formTag && isFormObject(field.Type) {
+			// nested form objects, e.g. deep object query parameters, are sent with the form names of their fields
+			fieldSchema = openapi3.NewSchemaRef("", s.formToSchema(field.Type))
+		} else {
+			fieldSchema = s.typeToSchemaRef(field.Type)
+		}
+		schema.Properties[field.name] = fieldSchema
+
+		rules := s.expandAliases(tags.Get(bindingTag))
+		if hasRule(rules, "required") {
+			schema.Required = append(schema.Required, field.name)
+		}
+
+		// constraints of the field can't be added to the referenced component, as they would apply to all its usages,
+		// but the annotations can be added to the schema wrapping the reference
+		if fieldSchema.Ref != "" {
+			if hasFieldAnnotations(field.StructField) {
+				wrapper := &openapi3.Schema{AllOf: openapi3.SchemaRefs{fieldSchema}}
+				applyFieldAnnotations(wrapper, field.StructField, field.Type)
+				schema.Properties[field.name] = openapi3.NewSchemaRef("", wrapper)
+			}
+			continue
+		}
+		if !field.asString {
+			s.applyBindingConstraints(fieldSchema.Value, rules)
+		}
+		applyFieldAnnotations(fieldSchema.Value, field.StructField, exampleType)
+
+		defaultValue, exists := tags.Lookup(defaultTag)
+		if exists {
+			fieldSchema.Value.Default = parseDefaultValue(defaultValue, exampleType)
+		}
+	}
+	return schema
+}
+
+// paramSchema returns a schema of a parameter passed as a plain string, e.g. in path.
+// Types implementing encoding.TextUnmarshaler are documented as strings, unless they have a dedicated schema (like time.Time).
+func (s *Schemas) paramSchema(t reflect.Type) *openapi3.Schema {
+	t = directType(t)
+	var schema *openapi3.Schema
+	if t != timeType && reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		schema = &openapi3.Schema{Type: typeAsString(t)}
+	} else {
+		schema = s.typeToSchema(t)
+	}
+	applyTypeAnnotations(schema, t)
+	return schema
+}
+
+func (s *Schemas) responseSchema(responseType reflect.Type) *openapi3.SchemaRef {
+	if responseType == bytesType || responseType.Implements(readerType) || responseType.Implements(binaryResponseType) {
+		return openapi3.NewSchemaRef("", openapi3.NewStringSchema().WithFormat("binary"))
+	}
+	return s.typeToSchemaRef(responseType)
+}
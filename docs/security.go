@@ -0,0 +1,59 @@
+package docs
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SecurityScheme documents the authentication method required by the endpoint,
+// e.g. checked by one of the authentication middlewares.
+type SecurityScheme struct {
+	// Name of the scheme in `components/securitySchemes`.
+	Name string
+	// Scheme is the OpenAPI definition of the scheme.
+	Scheme *openapi3.SecurityScheme
+	// Scopes required by the endpoint, used by OAuth2 and OpenID Connect schemes.
+	Scopes []string
+}
+
+// AddSecurityScheme registers the scheme in `components/securitySchemes` of the documentation.
+func (d *Docs) AddSecurityScheme(scheme *SecurityScheme) {
+	if d.OpenApi.Components == nil {
+		d.OpenApi.Components = &openapi3.Components{}
+	}
+	if d.OpenApi.Components.SecuritySchemes == nil {
+		d.OpenApi.Components.SecuritySchemes = openapi3.SecuritySchemes{}
+	}
+	d.OpenApi.Components.SecuritySchemes[scheme.Name] = &openapi3.SecuritySchemeRef{Value: scheme.Scheme}
+}
+
+// AddSecurity documents that the endpoint requires all the given schemes.
+// They are added to every alternative security requirement of the endpoint, or form a new one if there are none.
+func (e *Endpoint) AddSecurity(schemes ...*SecurityScheme) {
+	if len(schemes) == 0 {
+		return
+	}
+
+	var requirements openapi3.SecurityRequirements
+	if e.Security != nil {
+		requirements = *e.Security
+	}
+	if len(requirements) == 0 {
+		requirements = openapi3.SecurityRequirements{openapi3.SecurityRequirement{}}
+	}
+
+	// the requirements may be shared with other endpoints, e.g. from the group documentation, so they are copied
+	merged := make(openapi3.SecurityRequirements, 0, len(requirements))
+	for _, requirement := range requirements {
+		requirementCopy := make(openapi3.SecurityRequirement, len(requirement)+len(schemes))
+		for name, scopes := range requirement {
+			requirementCopy[name] = scopes
+		}
+		for _, scheme := range schemes {
+			if _, exists := requirementCopy[scheme.Name]; !exists {
+				requirementCopy[scheme.Name] = append([]string{}, scheme.Scopes...)
+			}
+		}
+		merged = append(merged, requirementCopy)
+	}
+	e.Security = &merged
+}
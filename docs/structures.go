@@ -2,17 +2,29 @@ package docs
 
 import (
 	"github.com/getkin/kin-openapi/openapi3"
+	"io/fs"
 )
 
 const (
 	defaultTag       = "default"
 	bindingTag       = "binding"
 	headerTag        = "header"
+	cookieTag        = "cookie"
+	pathTag          = "path"
+	queryTag         = "query"
+	uriTag           = "uri"
 	jsonTag          = "json"
+	formTag          = "form"
 	defaultStatusTag = "default_status"
 	statusCodesTag   = "status_codes"
+	docTag           = "doc"
+	titleTag         = "title"
+	exampleTag       = "example"
+	deprecatedTag    = "deprecated"
 )
 
+const validationsExtension = "x-validations"
+
 const (
 	// NoUrl is used to recognize whether the url should be cleared/ignored or initialized with the default value.
 	// If one of the urls in Options won't be filled, the default value will be used for it.
@@ -90,6 +102,19 @@ type Options struct {
 	// Security is specified by OpenAPI/Swagger standard version 3.
 	// It is empty as default.
 	Security openapi3.SecurityRequirements
+
+	// UI is the renderer of the interactive documentation: SwaggerUI, ReDoc or RapiDoc.
+	// If not set, the default value is SwaggerUI.
+	UI UI
+
+	// Assets contains the scripts and styles of the renderers, served under "<InteractiveUrl>/assets/", e.g. "swagger-ui/swagger-ui.css".
+	// If not set, the assets embedded in this package are used.
+	Assets fs.FS
+
+	// CDNAssets makes the interactive documentation load the scripts and styles from the CDN urls listed in the
+	// assets manifest, instead of serving them from Assets.
+	// It is disabled by default.
+	CDNAssets bool
 }
 
 var defaultOptions = &Options{
@@ -99,4 +124,5 @@ var defaultOptions = &Options{
 	JsonUrl:        "/docs.json",
 	YamlUrl:        "/docs.yaml",
 	Servers:        []string{"http://localhost:8080"},
+	UI:             SwaggerUI,
 }
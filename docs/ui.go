@@ -0,0 +1,110 @@
+package docs
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:generate go run ./internal/fetchassets assets
+
+// UI is the renderer of the interactive documentation.
+type UI string
+
+const (
+	SwaggerUI UI = "swagger-ui"
+	ReDoc     UI = "redoc"
+	RapiDoc   UI = "rapidoc"
+)
+
+const (
+	assetsDir      = "assets"
+	assetsManifest = "manifest.json"
+	assetsPath     = "assets"
+)
+
+// embeddedAssets contains the templates of the interactive documentation and the scripts and styles of the renderers.
+// The scripts, styles and their licenses are downloaded by `go generate` from the urls listed in the manifest,
+// and verified with the checksums pinned there. Missing checksums are added by the first download.
+//
+//go:embed assets
+var embeddedAssets embed.FS
+
+// asset is the entry of the assets manifest.
+type asset struct {
+	// Url is the CDN url of the asset, used by `go generate` and when Options.CDNAssets is enabled.
+	Url string `json:"url"`
+	// Sha256 is the hex encoded checksum of the asset content.
+	Sha256 string `json:"sha256,omitempty"`
+	// License marks the license files, served with the assets but not needed to render the documentation.
+	License bool `json:"license,omitempty"`
+}
+
+// manifest maps the names of the assets, e.g. "swagger-ui/swagger-ui.css", to their entries.
+var manifest = readManifest()
+
+func readManifest() map[string]asset {
+	data, err := embeddedAssets.ReadFile(path.Join(assetsDir, assetsManifest))
+	if err != nil {
+		panic(err)
+	}
+
+	assets := map[string]asset{}
+	if err = json.Unmarshal(data, &assets); err != nil {
+		panic(fmt.Sprintf("invalid assets manifest: %v", err))
+	}
+	return assets
+}
+
+// defaultAssets returns the embedded scripts and styles of the renderers.
+func defaultAssets() fs.FS {
+	assets, err := fs.Sub(embeddedAssets, assetsDir)
+	if err != nil {
+		panic(err)
+	}
+	return assets
+}
+
+// assets returns the names of the scripts and styles used by the renderer.
+func (u UI) assets() []string {
+	var names []string
+	for name, entry := range manifest {
+		if strings.HasPrefix(name, string(u)+"/") && !entry.License {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validate panics if the renderer is unknown or its scripts or styles are missing in the assets.
+// The assets are not checked if they are loaded from the CDN.
+func (u UI) validate(assets fs.FS, cdnAssets bool) {
+	u.template()
+	if cdnAssets {
+		return
+	}
+	for _, name := range u.assets() {
+		if _, err := fs.Stat(assets, name); err != nil {
+			panic(fmt.Sprintf("asset '%s' of the interactive documentation is missing; "+
+				"run `go generate` in the docs package, set Assets or enable CDNAssets in the options", name))
+		}
+	}
+}
+
+func (u UI) template() string {
+	switch u {
+	case SwaggerUI, ReDoc, RapiDoc:
+		data, err := embeddedAssets.ReadFile(path.Join(assetsDir, string(u)+".html"))
+		if err != nil {
+			panic(err)
+		}
+		return string(data)
+	default:
+		panic(fmt.Sprintf("unknown documentation UI: %q", u))
+	}
+}
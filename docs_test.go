@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"testing"
+	"testing/fstest"
+	"time"
 )
 
 func TestInteractiveDocsHandlerReturnsSubstitutedHtml(t *testing.T) {
@@ -27,8 +29,8 @@ func TestInteractiveDocsHandlerReturnsSubstitutedHtml(t *testing.T) {
 <head>
     <meta charset="utf-8">
     <title>Test - Documentation</title>
-    <link rel="stylesheet" type="text/css" href="https://unpkg.com/swagger-ui-dist@3/swagger-ui.css">
-    <script src="https://unpkg.com/swagger-ui-dist@3/swagger-ui-bundle.js" charset="UTF-8"></script>
+    <link rel="stylesheet" type="text/css" href="/path/to/interactive/docs/assets/swagger-ui/swagger-ui.css">
+    <script src="/path/to/interactive/docs/assets/swagger-ui/swagger-ui-bundle.js" charset="UTF-8"></script>
 </head>
 <body>
 <div id="swagger-ui"></div>
@@ -46,6 +48,75 @@ func TestInteractiveDocsHandlerReturnsSubstitutedHtml(t *testing.T) {
 </html>`, response.Body.String())
 }
 
+func TestInteractiveDocsEmbeddedAssets(t *testing.T) {
+	r := Router()
+	r.Docs.RegisterRoutes(r.rawRouter)
+
+	response := makeRequest(t, r, http.MethodGet, "/docs/assets/swagger-ui/swagger-ui.css")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Header().Get("Content-Type"), "text/css")
+	assert.Contains(t, response.Body.String(), ".swagger-ui")
+
+	response = makeRequest(t, r, http.MethodGet, "/docs/assets/swagger-ui/swagger-ui-bundle.js")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Header().Get("Content-Type"), "javascript")
+	assert.Contains(t, response.Body.String(), "SwaggerUIBundle")
+	assert.NotContains(t, response.Body.String(), "https://unpkg.com")
+
+	response = makeRequest(t, r, http.MethodGet, "/docs/assets/swagger-ui/LICENSE")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), "Apache License")
+
+	response = makeRequest(t, r, http.MethodGet, "/docs/assets/manifest.json")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestInteractiveDocsCustomAssets(t *testing.T) {
+	r := Router(&docs.Options{
+		Assets: fstest.MapFS{
+			"swagger-ui/swagger-ui.css":       {Data: []byte("body {}")},
+			"swagger-ui/swagger-ui-bundle.js": {Data: []byte("")},
+		},
+	})
+	r.Docs.RegisterRoutes(r.rawRouter)
+
+	response := makeRequest(t, r, http.MethodGet, "/docs/assets/swagger-ui/swagger-ui.css")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "body {}", response.Body.String())
+
+	assert.PanicsWithValue(t, "asset 'swagger-ui/swagger-ui-bundle.js' of the interactive documentation is missing; "+
+		"run `go generate` in the docs package, set Assets or enable CDNAssets in the options", func() {
+		Router(&docs.Options{
+			Assets: fstest.MapFS{
+				"swagger-ui/swagger-ui.css": {Data: []byte("body {}")},
+			},
+		})
+	})
+	assert.NotPanics(t, func() {
+		Router(&docs.Options{InteractiveUrl: docs.NoUrl, Assets: fstest.MapFS{}})
+	})
+}
+
+func TestInteractiveDocsRenderers(t *testing.T) {
+	r := Router(&docs.Options{UI: docs.ReDoc, Assets: fstest.MapFS{
+		"redoc/redoc.standalone.js": {Data: []byte("")},
+	}})
+	r.Docs.RegisterRoutes(r.rawRouter)
+	response := makeRequest(t, r, http.MethodGet, "/docs")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `<redoc spec-url="/docs.json"></redoc>`)
+	assert.Contains(t, response.Body.String(), `<script src="/docs/assets/redoc/redoc.standalone.js"`)
+
+	r = Router(&docs.Options{UI: docs.RapiDoc, CDNAssets: true})
+	r.Docs.RegisterRoutes(r.rawRouter)
+	response = makeRequest(t, r, http.MethodGet, "/docs")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `<rapi-doc spec-url="/docs.json"`)
+	assert.Contains(t, response.Body.String(), `src="https://unpkg.com/rapidoc@9.3.4/dist/rapidoc-min.js"`)
+
+	assert.Panics(t, func() { Router(&docs.Options{UI: "unknown"}) })
+}
+
 func TestDocsTags(t *testing.T) {
 	handler := func() string {
 		return "Hello World!"
@@ -63,6 +134,79 @@ func TestDocsTags(t *testing.T) {
 	assert.Equal(t, []string{"my", "shops", "shop"}, doc.Paths["/my/shops/shop/{name}/"].Get.Tags)
 }
 
+func TestDocsGroupDefaults(t *testing.T) {
+	handler := func() string { return "" }
+	security := openapi3.NewSecurityRequirements().With(openapi3.SecurityRequirement{"HTTPBearer": []string{}})
+	forbidden := "forbidden"
+	tenantParam := openapi3.NewHeaderParameter("X-Tenant").WithRequired(true)
+
+	r := Router()
+	admin := r.Group("/admin", &docs.Endpoint{
+		Tags:       []string{"admin"},
+		Security:   security,
+		Parameters: openapi3.Parameters{{Value: tenantParam}},
+		Responses: openapi3.Responses{
+			"403": {Value: &openapi3.Response{Description: &forbidden}},
+		},
+		Extensions: map[string]interface{}{"x-internal": true, "x-owner": "admins"},
+	})
+	admin.GET("/users", handler)
+	admin.GET("/public", handler, &docs.Endpoint{
+		Tags:       []string{"public"},
+		Security:   openapi3.NewSecurityRequirements(),
+		Extensions: map[string]interface{}{"x-internal": false},
+	})
+	admin.Group("/legacy", &docs.Endpoint{Deprecated: true}).GET("/users", handler)
+	r.GET("/users", handler)
+
+	doc := generateDocs(t, r)
+
+	users := doc.Paths["/admin/users"].Get
+	assert.Equal(t, []string{"admin"}, users.Tags)
+	assert.Equal(t, *security, *users.Security)
+	assert.True(t, users.Parameters.GetByInAndName("header", "X-Tenant").Required)
+	assert.Equal(t, forbidden, *users.Responses.Get(403).Value.Description)
+	assert.NotNil(t, users.Responses.Get(200))
+	assert.Equal(t, true, users.Extensions["x-internal"])
+	assert.False(t, users.Deprecated)
+
+	public := doc.Paths["/admin/public"].Get
+	assert.Equal(t, []string{"public"}, public.Tags)
+	assert.Empty(t, *public.Security)
+	assert.Equal(t, false, public.Extensions["x-internal"])
+	assert.Equal(t, "admins", public.Extensions["x-owner"])
+	assert.NotNil(t, public.Responses.Get(403))
+
+	legacy := doc.Paths["/admin/legacy/users"].Get
+	assert.True(t, legacy.Deprecated)
+	assert.Equal(t, []string{"admin"}, legacy.Tags)
+	assert.NotNil(t, legacy.Responses.Get(403))
+
+	root := doc.Paths["/users"].Get
+	assert.Equal(t, []string{"users"}, root.Tags)
+	assert.Nil(t, root.Security)
+	assert.Nil(t, root.Responses.Get(403))
+
+	assert.Panics(t, func() { r.Group("/api", &docs.Endpoint{}, &docs.Endpoint{}) })
+}
+
+func TestRouteOptions(t *testing.T) {
+	handler := func() string { return "" }
+
+	r := Router()
+	r.GET("/nil", handler, nil)
+	r.GET("/documented", handler, &docs.Endpoint{Summary: "documented"}, WithoutMiddleware())
+	r.GET("/with-docs", handler, WithDocs(&docs.Endpoint{Summary: "with docs"}))
+
+	doc := generateDocs(t, r)
+	assert.NotNil(t, doc.Paths["/nil"].Get)
+	assert.Equal(t, "documented", doc.Paths["/documented"].Get.Summary)
+	assert.Equal(t, "with docs", doc.Paths["/with-docs"].Get.Summary)
+
+	assert.Panics(t, func() { r.GET("/twice", handler, &docs.Endpoint{}, &docs.Endpoint{}) })
+	assert.Panics(t, func() { r.GET("/twice-with-docs", handler, &docs.Endpoint{}, WithDocs(&docs.Endpoint{})) })
+}
+
 func generateDocs(t *testing.T, r *RootRouter) *openapi3.T {
 	r.Docs.RegisterRoutes(r.rawRouter)
 
@@ -84,7 +228,7 @@ func TestDocsWithoutSecuritySchema(t *testing.T) {
 
 	doc := generateDocs(t, r)
 
-	assert.Nil(t, doc.Components)
+	assert.Nil(t, doc.Components.SecuritySchemes)
 	assert.Nil(t, doc.Security)
 }
 
@@ -108,15 +252,12 @@ func TestDocsWithGlobalSecuritySchema(t *testing.T) {
 
 	doc := generateDocs(t, r)
 
-	assert.Equal(t, openapi3.Components{
-		Extensions: map[string]interface{}{},
-		SecuritySchemes: openapi3.SecuritySchemes{"HTTPBearer": &openapi3.SecuritySchemeRef{Value: &openapi3.SecurityScheme{
-			Extensions:   map[string]interface{}{},
-			Type:         "http",
-			Scheme:       "bearer",
-			BearerFormat: "JWT",
-		}}},
-	}, *doc.Components)
+	assert.Equal(t, openapi3.SecuritySchemes{"HTTPBearer": &openapi3.SecuritySchemeRef{Value: &openapi3.SecurityScheme{
+		Extensions:   map[string]interface{}{},
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+	}}}, doc.Components.SecuritySchemes)
 	assert.Equal(t, openapi3.SecurityRequirements{openapi3.SecurityRequirement{"HTTPBearer": []string{}}}, doc.Security)
 }
 
@@ -140,16 +281,314 @@ func TestDocsWithEndpointSecuritySchema(t *testing.T) {
 
 	doc := generateDocs(t, r)
 
-	assert.Equal(t, openapi3.Components{
-		Extensions: map[string]interface{}{},
-		SecuritySchemes: openapi3.SecuritySchemes{"HTTPBearer": &openapi3.SecuritySchemeRef{Value: &openapi3.SecurityScheme{
-			Extensions:   map[string]interface{}{},
-			Type:         "http",
-			Scheme:       "bearer",
-			BearerFormat: "JWT",
-		}}},
-	}, *doc.Components)
+	assert.Equal(t, openapi3.SecuritySchemes{"HTTPBearer": &openapi3.SecuritySchemeRef{Value: &openapi3.SecurityScheme{
+		Extensions:   map[string]interface{}{},
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+	}}}, doc.Components.SecuritySchemes)
 	assert.Nil(t, doc.Security)
 	assert.Nil(t, doc.Paths["/my/example1"].Post.Security)
 	assert.Equal(t, openapi3.SecurityRequirements{openapi3.SecurityRequirement{"HTTPBearer": []string{}}}, *doc.Paths["/my/example2"].Post.Security)
 }
+
+func TestDocsBindingConstraints(t *testing.T) {
+	type request struct {
+		Age      int               `json:"age" binding:"gte=18,lt=100"`
+		Score    float64           `json:"score" binding:"gt=0,lte=1"`
+		Name     string            `json:"name" binding:"required,min=2,max=20,alphanum"`
+		Code     string            `json:"code" binding:"len=4,startswith=A-"`
+		Email    string            `json:"email" binding:"omitempty,email"`
+		Website  string            `json:"website" binding:"url"`
+		Id       string            `json:"id" binding:"uuid4"`
+		Level    int               `json:"level" binding:"oneof=1 2 3"`
+		Color    string            `json:"color" binding:"oneof=red 'dark blue'"`
+		Tags     []string          `json:"tags" binding:"min=1,max=5,unique,dive,min=3"`
+		Labels   map[string]string `json:"labels" binding:"max=10,dive,keys,alpha,endkeys,email"`
+		Optional *int              `json:"optional" binding:"omitempty,min=1"`
+		Emails   []string          `json:"emails" binding:"dive,required,email"`
+	}
+
+	r := Router()
+	r.POST("/constraints", func(req *request) string { return "" })
+
+	doc := generateDocs(t, r)
+	schema := doc.Paths["/constraints"].Post.RequestBody.Value.Content.Get("application/json").Schema.Value
+	properties := schema.Properties
+	assert.Equal(t, []string{"name"}, schema.Required)
+
+	age := properties["age"].Value
+	assert.Equal(t, 18.0, *age.Min)
+	assert.False(t, age.ExclusiveMin)
+	assert.Equal(t, 100.0, *age.Max)
+	assert.True(t, age.ExclusiveMax)
+
+	score := properties["score"].Value
+	assert.Equal(t, 0.0, *score.Min)
+	assert.True(t, score.ExclusiveMin)
+	assert.Equal(t, 1.0, *score.Max)
+
+	name := properties["name"].Value
+	assert.Equal(t, uint64(2), name.MinLength)
+	assert.Equal(t, uint64(20), *name.MaxLength)
+	assert.Equal(t, "^[a-zA-Z0-9]+$", name.Pattern)
+
+	code := properties["code"].Value
+	assert.Equal(t, uint64(4), code.MinLength)
+	assert.Equal(t, uint64(4), *code.MaxLength)
+	assert.Equal(t, `^A-`, code.Pattern)
+
+	assert.Equal(t, "email", properties["email"].Value.Format)
+	assert.Equal(t, "uri", properties["website"].Value.Format)
+	assert.Equal(t, "uuid", properties["id"].Value.Format)
+	assert.Equal(t, []interface{}{1.0, 2.0, 3.0}, properties["level"].Value.Enum)
+	assert.Equal(t, []interface{}{"red", "dark blue"}, properties["color"].Value.Enum)
+
+	tags := properties["tags"].Value
+	assert.Equal(t, uint64(1), tags.MinItems)
+	assert.Equal(t, uint64(5), *tags.MaxItems)
+	assert.True(t, tags.UniqueItems)
+	assert.Equal(t, uint64(3), tags.Items.Value.MinLength)
+
+	labels := properties["labels"].Value
+	assert.Equal(t, uint64(10), *labels.MaxProps)
+	assert.Equal(t, "email", labels.AdditionalProperties.Schema.Value.Format)
+	assert.Empty(t, labels.AdditionalProperties.Schema.Value.Pattern)
+
+	assert.Equal(t, 1.0, *properties["optional"].Value.Min)
+
+	emails := properties["emails"].Value
+	assert.Empty(t, emails.Format)
+	assert.Equal(t, "email", emails.Items.Value.Format)
+}
+
+type treeNode struct {
+	Name     string      `json:"name"`
+	Parent   *treeNode   `json:"parent"`
+	Children []*treeNode `json:"children" binding:"dive,required"`
+}
+
+func TestDocsComponentSchemas(t *testing.T) {
+	type item struct {
+		Id int `json:"id"`
+	}
+	r := Router()
+	r.POST("/tree", func(node *treeNode) []item { return nil })
+	r.GET("/anonymous", func() struct {
+		Item item `json:"item" binding:"required"`
+	} {
+		return struct {
+			Item item `json:"item" binding:"required"`
+		}{}
+	})
+
+	doc := generateDocs(t, r)
+
+	body := doc.Paths["/tree"].Post.RequestBody.Value.Content.Get("application/json").Schema
+	assert.Equal(t, "#/components/schemas/treeNode", body.Ref)
+
+	node := doc.Components.Schemas["treeNode"].Value
+	assert.Equal(t, "#/components/schemas/treeNode", node.Properties["parent"].Ref)
+	assert.Equal(t, "#/components/schemas/treeNode", node.Properties["children"].Value.Items.Ref)
+	assert.Same(t, node, node.Properties["parent"].Value)
+
+	response := doc.Paths["/tree"].Post.Responses.Get(200).Value.Content.Get("application/json").Schema.Value
+	assert.Equal(t, "#/components/schemas/item", response.Items.Ref)
+
+	anonymous := doc.Paths["/anonymous"].Get.Responses.Get(200).Value.Content.Get("application/json").Schema
+	assert.Empty(t, anonymous.Ref)
+	assert.Equal(t, "#/components/schemas/item", anonymous.Value.Properties["item"].Ref)
+	assert.Equal(t, []string{"item"}, anonymous.Value.Required)
+}
+
+func TestDocsComponentNamesAreUnique(t *testing.T) {
+	r := Router()
+	{
+		type item struct {
+			Id int `json:"id"`
+		}
+		r.GET("/first", func() *item { return nil })
+	}
+	{
+		type item struct {
+			Name string `json:"name"`
+		}
+		r.GET("/second", func() *item { return nil })
+	}
+
+	doc := generateDocs(t, r)
+
+	first := doc.Paths["/first"].Get.Responses.Get(200).Value.Content.Get("application/json").Schema
+	second := doc.Paths["/second"].Get.Responses.Get(200).Value.Content.Get("application/json").Schema
+	assert.Equal(t, "#/components/schemas/item", first.Ref)
+	assert.Equal(t, "#/components/schemas/gnext_item", second.Ref)
+	assert.Contains(t, first.Value.Properties, "id")
+	assert.Contains(t, second.Value.Properties, "name")
+}
+
+type Audit struct {
+	CreatedBy string `json:"created_by"`
+	UpdatedBy string `json:"updated_by"`
+}
+
+type pagination struct {
+	Page  int `json:"page"`
+	Total int `json:"total"`
+}
+
+func TestDocsStructFieldsAsInJSON(t *testing.T) {
+	type response struct {
+		Audit
+		*pagination
+		Id        int64  `json:"id,string"`
+		Name      string `json:"name,omitempty" binding:"required"`
+		Untagged  bool
+		Skipped   string `json:"-"`
+		Dash      string `json:"-,"`
+		Total     string `json:"total"`
+		UpdatedBy string
+		Callback  func()
+		hidden    string
+	}
+
+	r := Router()
+	r.GET("/fields", func() *response { return nil })
+
+	doc := generateDocs(t, r)
+
+	schema := doc.Components.Schemas["response"].Value
+	var names []string
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"created_by", "updated_by", "page", "total", "id", "name", "Untagged", "-", "UpdatedBy"}, names)
+	assert.Equal(t, []string{"name"}, schema.Required)
+	assert.Equal(t, "string", schema.Properties["id"].Value.Type)
+	assert.Equal(t, "string", schema.Properties["total"].Value.Type)
+	assert.Equal(t, "boolean", schema.Properties["Untagged"].Value.Type)
+}
+
+type orderStatus string
+
+func (orderStatus) Describe() string {
+	return "status of the order: new or paid"
+}
+
+func (orderStatus) Example() interface{} {
+	return "paid"
+}
+
+func TestDocsAnnotations(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type order struct {
+		Id       int          `json:"id" doc:"identifier of the order" example:"12"`
+		Price    float64      `json:"price,string" example:"9.99"`
+		Items    []string     `json:"items" example:"[\"apple\"]"`
+		Legacy   bool         `json:"legacy" title:"Legacy flag" deprecated:"true"`
+		Status   orderStatus  `json:"status"`
+		Address  address      `json:"address" doc:"shipping address"`
+		Shipping *orderStatus `json:"shipping" doc:"status of the shipping"`
+	}
+	type query struct {
+		Query
+		Page int `form:"page" doc:"page number" example:"2" deprecated:"true"`
+	}
+	type headers struct {
+		Headers
+		RequestId string `header:"X-Request-Id" doc:"id of the request"`
+	}
+	type path struct {
+		Path
+		Status orderStatus `uri:"status" doc:"order status"`
+	}
+
+	r := Router()
+	r.POST("/orders/:status", func(o *order, q *query, h *headers, p *path) *order { return nil })
+
+	doc := generateDocs(t, r)
+
+	properties := doc.Components.Schemas["order"].Value.Properties
+	assert.Equal(t, "identifier of the order", properties["id"].Value.Description)
+	assert.Equal(t, 12.0, properties["id"].Value.Example)
+	assert.Equal(t, "9.99", properties["price"].Value.Example)
+	assert.Equal(t, []interface{}{"apple"}, properties["items"].Value.Example)
+	assert.Equal(t, "Legacy flag", properties["legacy"].Value.Title)
+	assert.True(t, properties["legacy"].Value.Deprecated)
+	assert.Equal(t, "status of the order: new or paid", properties["status"].Value.Description)
+	assert.Equal(t, "paid", properties["status"].Value.Example)
+	assert.Equal(t, "status of the shipping", properties["shipping"].Value.Description)
+
+	addressSchema := properties["address"]
+	assert.Empty(t, addressSchema.Ref)
+	assert.Equal(t, "shipping address", addressSchema.Value.Description)
+	assert.Equal(t, "#/components/schemas/address", addressSchema.Value.AllOf[0].Ref)
+	assert.Empty(t, doc.Components.Schemas["address"].Value.Description)
+
+	parameters := doc.Paths["/orders/{status}"].Post.Parameters
+	page := parameters.GetByInAndName("query", "page")
+	assert.Equal(t, "page number", page.Description)
+	assert.Equal(t, 2.0, page.Example)
+	assert.True(t, page.Deprecated)
+	assert.Equal(t, "id of the request", parameters.GetByInAndName("header", "X-Request-Id").Description)
+
+	status := parameters.GetByInAndName("path", "status")
+	assert.Equal(t, "order status", status.Description)
+	assert.Equal(t, "status of the order: new or paid", status.Schema.Value.Description)
+}
+
+func TestDocsQueryParameters(t *testing.T) {
+	type paging struct {
+		Page  int `form:"page,default=1" binding:"min=1"`
+		Limit int `form:"limit" default:"20" binding:"max=100"`
+	}
+	type query struct {
+		Query
+		paging
+		Search  string    `form:"search" binding:"required"`
+		Tags    []string  `form:"tag" binding:"max=3,dive,required,min=2"`
+		Ids     []int     `form:"id,default=5"`
+		Since   time.Time `form:"since"`
+		Verbose bool
+		Ignored string `form:"-"`
+	}
+
+	r := Router()
+	r.GET("/search", func(q *query) string { return "" })
+
+	doc := generateDocs(t, r)
+	parameters := doc.Paths["/search"].Get.Parameters
+	require.Len(t, parameters, 7)
+
+	page := parameters.GetByInAndName("query", "page")
+	assert.False(t, page.Required)
+	assert.Equal(t, "integer", page.Schema.Value.Type)
+	assert.Equal(t, 1.0, page.Schema.Value.Default)
+	assert.Equal(t, 1.0, *page.Schema.Value.Min)
+
+	limit := parameters.GetByInAndName("query", "limit")
+	assert.Equal(t, 20.0, limit.Schema.Value.Default)
+	assert.Equal(t, 100.0, *limit.Schema.Value.Max)
+
+	search := parameters.GetByInAndName("query", "search")
+	assert.True(t, search.Required)
+	assert.Equal(t, "string", search.Schema.Value.Type)
+
+	tags := parameters.GetByInAndName("query", "tag")
+	assert.Equal(t, "array", tags.Schema.Value.Type)
+	assert.Equal(t, "string", tags.Schema.Value.Items.Value.Type)
+	assert.Equal(t, uint64(3), *tags.Schema.Value.MaxItems)
+	assert.False(t, tags.Required)
+	assert.Equal(t, uint64(2), tags.Schema.Value.Items.Value.MinLength)
+	assert.Zero(t, tags.Schema.Value.MinLength)
+	assert.Equal(t, "form", tags.Style)
+	assert.True(t, *tags.Explode)
+
+	ids := parameters.GetByInAndName("query", "id")
+	assert.Equal(t, "integer", ids.Schema.Value.Items.Value.Type)
+	assert.Equal(t, []interface{}{5.0}, ids.Schema.Value.Default)
+
+	assert.Equal(t, "date-time", parameters.GetByInAndName("query", "since").Schema.Value.Format)
+	assert.Equal(t, "boolean", parameters.GetByInAndName("query", "Verbose").Schema.Value.Type)
+}
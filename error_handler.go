@@ -23,6 +23,18 @@ func (h errorHandlers) setup(handler interface{}) {
 	h[ht.In(0)] = reflect.ValueOf(handler)
 }
 
+// setupDefault replaces the handler of errors without more specific handlers, like DefaultErrorHandler.
+// It has to accept any error, otherwise the other errors would be left without a handler.
+func (h errorHandlers) setupDefault(handler interface{}) {
+	ht := reflect.TypeOf(handler)
+	validateErrorHandler(ht)
+	if ht.In(0) != errorInterfaceType {
+		panic(fmt.Sprintf("default error handler '%s' must accept argument of type 'error', got type '%s'", ht, ht.In(0)))
+	}
+
+	h[errorInterfaceType] = reflect.ValueOf(handler)
+}
+
 func (h errorHandlers) copy() errorHandlers {
 	newHandlers := make(errorHandlers, len(h))
 	for typ, value := range h {
@@ -60,9 +72,18 @@ func DefaultErrorHandler(err error) (status Status, response *DefaultErrorRespon
 			response.Details = append(response.Details, fmt.Sprintf("field validation for '%s' failed on the '%s' tag with value '%s'",
 				validationError.Field(), validationError.ActualTag(), validationError.Param()))
 		}
+	case *BadRequest:
+		status = http.StatusBadRequest
+		response.Message = err.Error()
 	case *NotFound:
 		status = http.StatusNotFound
 		response.Message = err.Error()
+	case *Unauthorized:
+		status = http.StatusUnauthorized
+		response.Message = err.Error()
+	case *UnsupportedMediaType:
+		status = http.StatusUnsupportedMediaType
+		response.Message = err.Error()
 	case *HandlerPanicked:
 		errLog.Printf("panic recovered: %v\n%s%s", e.Value, e.StackTrace, resetColor)
 	default:
@@ -4,6 +4,18 @@ import "fmt"
 
 type NotFound struct{ error }
 
+// BadRequest is returned when the request cannot be bound, e.g. a query parameter has an invalid value.
+// The default error handler responds to it with 400.
+type BadRequest struct{ error }
+
+// Unauthorized is returned when the request has no valid credentials, e.g. by the authentication middlewares.
+// The default error handler responds to it with 401.
+type Unauthorized struct{ error }
+
+// UnsupportedMediaType is returned when the request body has a content type not consumed by the route.
+// The default error handler responds to it with 415.
+type UnsupportedMediaType struct{ error }
+
 type HandlerPanicked struct {
 	Value      interface{}
 	StackTrace []byte
@@ -0,0 +1,170 @@
+package gnext
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// File is a response sent as raw file content, e.g. a report or an image.
+// If the status is 200 and the content is seekable, range and conditional requests are supported.
+type File struct {
+	// Content of the file. If it implements io.Closer, it is closed after sending.
+	Content io.Reader
+	// Path to the file on disk. It is used when Content is nil.
+	Path string
+	// Name of the file. If set, the file is sent as an attachment with this name.
+	Name string
+	// ContentType of the file. If empty, it is detected from the name or the content.
+	ContentType string
+	// ModTime is the modification time used for `If-Modified-Since` requests.
+	ModTime time.Time
+}
+
+func (f File) GnBinaryResponse() {}
+
+const mimeOctetStream = "application/octet-stream"
+
+var (
+	bytesType  = reflect.TypeOf([]byte{})
+	readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	fileType   = reflect.TypeOf(File{})
+)
+
+// isBinaryType reports whether the response of given type is sent as raw bytes instead of encoded content.
+func isBinaryType(t reflect.Type) bool {
+	return t == bytesType || t.Implements(readerType) || directType(t) == fileType
+}
+
+// renderBinary writes []byte, io.Reader or File response.
+func renderBinary(ctx *gin.Context, status int, value reflect.Value) {
+	if isNilable(value.Kind()) && value.IsNil() {
+		ctx.Status(status)
+		return
+	}
+
+	switch v := value.Interface().(type) {
+	case []byte:
+		serveContent(ctx, status, "", time.Time{}, bytes.NewReader(v))
+	case File:
+		serveFile(ctx, status, &v)
+	case *File:
+		serveFile(ctx, status, v)
+	case io.Reader:
+		if closer, ok := v.(io.Closer); ok {
+			defer closer.Close()
+		}
+		serveContent(ctx, status, "", time.Time{}, v)
+	}
+}
+
+// targetResponseSetter returns the setter of the response returned from the target handler.
+func targetResponseSetter(responseType reflect.Type, contextIndex int) argSetter {
+	if directType(responseType) == fileType {
+		return fileResponseSetter(contextIndex)
+	}
+	return responseSetter(contextIndex)
+}
+
+// fileResponseSetter sets the File response, opening the file from its path first. If the file cannot be opened,
+// *NotFound error is set instead, so it is handled by the error handlers, like an error returned from the handler.
+func fileResponseSetter(contextIndex int) argSetter {
+	setResponse := responseSetter(contextIndex)
+	return func(value *reflect.Value, ctx *callContext) {
+		var file *File
+		switch v := value.Interface().(type) {
+		case File:
+			file = &v
+		case *File:
+			file = v
+		}
+		if file == nil || file.Content != nil {
+			setResponse(value, ctx)
+			return
+		}
+
+		opened, err := openFile(file)
+		if err != nil {
+			errValue := reflect.ValueOf(&err).Elem()
+			ctx.error = &errValue
+			return
+		}
+		ctx.closeFile()
+		ctx.file = opened.Content.(*os.File)
+
+		openedValue := reflect.ValueOf(*opened)
+		if value.Kind() == reflect.Ptr {
+			openedValue = reflect.ValueOf(opened)
+		}
+		setResponse(&openedValue, ctx)
+	}
+}
+
+// openFile returns the copy of the file with the content opened from its path.
+func openFile(file *File) (*File, error) {
+	osFile, err := os.Open(file.Path)
+	if err != nil {
+		return nil, &NotFound{fmt.Errorf("cannot open file: %w", err)}
+	}
+
+	opened := *file
+	opened.Content = osFile
+	if info, err := osFile.Stat(); err == nil && opened.ModTime.IsZero() {
+		opened.ModTime = info.ModTime()
+	}
+	return &opened, nil
+}
+
+func serveFile(ctx *gin.Context, status int, file *File) {
+	if file.Content == nil {
+		opened, err := openFile(file)
+		if err != nil {
+			_ = ctx.AbortWithError(http.StatusNotFound, err)
+			return
+		}
+		file = opened
+	}
+	if closer, ok := file.Content.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	name := file.Name
+	if name == "" && file.Path != "" {
+		name = filepath.Base(file.Path)
+	}
+	if file.ContentType != "" {
+		ctx.Header("Content-Type", file.ContentType)
+	}
+	if file.Name != "" {
+		ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	}
+	serveContent(ctx, status, name, file.ModTime, file.Content)
+}
+
+// serveContent uses http.ServeContent for seekable content sent with status 200, to support range requests.
+// Otherwise, the content is just copied to the response.
+func serveContent(ctx *gin.Context, status int, name string, modTime time.Time, content io.Reader) {
+	if seeker, ok := content.(io.ReadSeeker); ok && status == http.StatusOK {
+		http.ServeContent(ctx.Writer, ctx.Request, name, modTime, seeker)
+		return
+	}
+
+	if ctx.Writer.Header().Get("Content-Type") == "" {
+		contentType := mime.TypeByExtension(filepath.Ext(name))
+		if contentType == "" {
+			contentType = mimeOctetStream
+		}
+		ctx.Header("Content-Type", contentType)
+	}
+	ctx.Status(status)
+	if _, err := io.Copy(ctx.Writer, content); err != nil {
+		_ = ctx.Error(err)
+	}
+}
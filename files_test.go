@@ -0,0 +1,107 @@
+package gnext
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBytesResponse(t *testing.T) {
+	r := Router()
+	r.GET("/bytes", func() []byte {
+		return []byte("hello world")
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/bytes")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.Equal(t, "hello world", response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/bytes", withHeader("Range", "bytes=6-"))
+	assert.Equal(t, http.StatusPartialContent, response.Code)
+	assert.Equal(t, "world", response.Body.String())
+}
+
+func TestReaderResponse(t *testing.T) {
+	r := Router()
+	r.POST("/reader", func() (io.Reader, Status) {
+		return io.MultiReader(strings.NewReader("a"), strings.NewReader("b")), http.StatusCreated
+	})
+
+	response := makeRequest(t, r, http.MethodPost, "/reader")
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Equal(t, "application/octet-stream", response.Header().Get("Content-Type"))
+	assert.Equal(t, "ab", response.Body.String())
+}
+
+func TestFileResponse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.csv")
+	require.NoError(t, os.WriteFile(path, []byte("a,b\n1,2\n"), 0644))
+
+	r := Router()
+	r.GET("/content", func() *File {
+		return &File{Content: strings.NewReader("content"), Name: "data.bin", ContentType: "application/x-custom"}
+	})
+	r.GET("/path", func() File {
+		return File{Path: path}
+	})
+	r.GET("/missing", func() File {
+		return File{Path: filepath.Join(t.TempDir(), "missing")}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/content", withHeader("Range", "bytes=0-3"))
+	assert.Equal(t, http.StatusPartialContent, response.Code)
+	assert.Equal(t, "application/x-custom", response.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=data.bin`, response.Header().Get("Content-Disposition"))
+	assert.Equal(t, "cont", response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/path")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Header().Get("Content-Type"), "text/csv")
+	assert.Empty(t, response.Header().Get("Content-Disposition"))
+	assert.Equal(t, "a,b\n1,2\n", response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/missing")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestMissingFileErrorHandler(t *testing.T) {
+	type fileError struct {
+		Message string `json:"message"`
+	}
+
+	r := Router()
+	r.OnError(func(err *NotFound) (*fileError, Status) {
+		return &fileError{Message: err.Error()}, http.StatusTeapot
+	})
+	r.GET("/missing", func() (*File, error) {
+		return &File{Path: filepath.Join(t.TempDir(), "missing")}, nil
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/missing")
+	assert.Equal(t, http.StatusTeapot, response.Code)
+	assert.Contains(t, response.Body.String(), "cannot open file")
+	assert.Empty(t, response.Header().Get("Content-Disposition"))
+}
+
+func TestBinaryResponseDocs(t *testing.T) {
+	r := Router()
+	r.GET("/bytes", func() []byte { return nil })
+	r.GET("/reader", func() io.ReadCloser { return nil })
+	r.GET("/file", func() *File { return nil })
+
+	doc := generateDocs(t, r)
+
+	for _, path := range []string{"/bytes", "/reader", "/file"} {
+		content := doc.Paths[path].Get.Responses.Get(200).Value.Content
+		require.Len(t, content, 1, path)
+		schema := content.Get("application/octet-stream").Schema.Value
+		assert.Equal(t, "string", schema.Type, path)
+		assert.Equal(t, "binary", schema.Format, path)
+	}
+}
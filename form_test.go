@@ -0,0 +1,97 @@
+package gnext
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type signUpForm struct {
+	Form
+	Name  string `form:"name" binding:"required"`
+	Age   int    `form:"age"`
+	Terms bool   `form:"terms"`
+}
+
+type uploadForm struct {
+	MultipartForm
+	Description string                  `form:"description"`
+	File        *multipart.FileHeader   `form:"file" binding:"required"`
+	Attachments []*multipart.FileHeader `form:"attachments"`
+}
+
+func TestFormBody(t *testing.T) {
+	r := Router()
+	r.POST("/sign-up", func(form *signUpForm) string {
+		return fmt.Sprintf("%s %d %t", form.Name, form.Age, form.Terms)
+	})
+
+	values := url.Values{"name": {"John"}, "age": {"30"}, "terms": {"true"}}
+	response := makeRequest(t, r, http.MethodPost, "/sign-up",
+		withHeader("Content-Type", "application/x-www-form-urlencoded"), withRawBody(values.Encode()))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"John 30 true"`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodPost, "/sign-up",
+		withHeader("Content-Type", "application/x-www-form-urlencoded"), withRawBody("age=30"))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestMultipartFormBody(t *testing.T) {
+	r := Router()
+	r.POST("/upload", func(form *uploadForm) string {
+		file, err := form.File.Open()
+		require.NoError(t, err)
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		return form.Description + ": " + form.File.Filename + " " + string(content)
+	})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	require.NoError(t, writer.WriteField("description", "report"))
+	fileWriter, err := writer.CreateFormFile("file", "report.txt")
+	require.NoError(t, err)
+	_, err = fileWriter.Write([]byte("content"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	response := makeRequest(t, r, http.MethodPost, "/upload",
+		withHeader("Content-Type", writer.FormDataContentType()), withRawBody(body.String()))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"report: report.txt content"`, response.Body.String())
+}
+
+func TestFormBodyDocs(t *testing.T) {
+	r := Router()
+	r.POST("/sign-up", func(form *signUpForm) {})
+	r.POST("/upload", func(form *uploadForm) {})
+
+	doc := generateDocs(t, r)
+
+	content := doc.Paths["/sign-up"].Post.RequestBody.Value.Content
+	require.Len(t, content, 1)
+	schema := content.Get("application/x-www-form-urlencoded").Schema.Value
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, "string", schema.Properties["name"].Value.Type)
+	assert.Equal(t, "integer", schema.Properties["age"].Value.Type)
+	assert.Equal(t, "boolean", schema.Properties["terms"].Value.Type)
+	assert.Equal(t, []string{"name"}, schema.Required)
+
+	content = doc.Paths["/upload"].Post.RequestBody.Value.Content
+	require.Len(t, content, 1)
+	schema = content.Get("multipart/form-data").Schema.Value
+	assert.Equal(t, "string", schema.Properties["description"].Value.Type)
+	assert.Equal(t, "string", schema.Properties["file"].Value.Type)
+	assert.Equal(t, "binary", schema.Properties["file"].Value.Format)
+	assert.Equal(t, "array", schema.Properties["attachments"].Value.Type)
+	assert.Equal(t, "binary", schema.Properties["attachments"].Value.Items.Value.Format)
+	assert.Equal(t, []string{"file"}, schema.Required)
+}
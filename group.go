@@ -2,6 +2,7 @@ package gnext
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/meteran/gnext/docs"
 	"net/http"
 )
@@ -12,6 +13,10 @@ type routerGroup struct {
 	middlewares   middlewares
 	Docs          *docs.Docs
 	errorHandlers errorHandlers
+	mediaTypes    mediaTypes
+	renderers     renderers
+	validate      *validator.Validate
+	doc           *docs.Endpoint
 }
 
 func (g *routerGroup) OnError(errorHandler interface{}) IRoutes {
@@ -19,59 +24,94 @@ func (g *routerGroup) OnError(errorHandler interface{}) IRoutes {
 	return g
 }
 
-func (g *routerGroup) Any(path string, handler interface{}, doc ...*docs.Endpoint) IRoutes {
+func (g *routerGroup) Any(path string, handler interface{}, options ...RouteOption) IRoutes {
 	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions} {
-		g.Handle(method, path, handler, doc...)
+		g.Handle(method, path, handler, options...)
 	}
 	return g
 }
 
-func (g *routerGroup) DELETE(path string, handler interface{}, doc ...*docs.Endpoint) IRoutes {
-	return g.Handle(http.MethodDelete, path, handler, doc...)
+func (g *routerGroup) DELETE(path string, handler interface{}, options ...RouteOption) IRoutes {
+	return g.Handle(http.MethodDelete, path, handler, options...)
 }
 
-func (g *routerGroup) PATCH(path string, handler interface{}, doc ...*docs.Endpoint) IRoutes {
-	return g.Handle(http.MethodPatch, path, handler, doc...)
+func (g *routerGroup) PATCH(path string, handler interface{}, options ...RouteOption) IRoutes {
+	return g.Handle(http.MethodPatch, path, handler, options...)
 }
 
-func (g *routerGroup) PUT(path string, handler interface{}, doc ...*docs.Endpoint) IRoutes {
-	return g.Handle(http.MethodPut, path, handler, doc...)
+func (g *routerGroup) PUT(path string, handler interface{}, options ...RouteOption) IRoutes {
+	return g.Handle(http.MethodPut, path, handler, options...)
 }
 
-func (g *routerGroup) OPTIONS(path string, handler interface{}, doc ...*docs.Endpoint) IRoutes {
-	return g.Handle(http.MethodOptions, path, handler, doc...)
+func (g *routerGroup) OPTIONS(path string, handler interface{}, options ...RouteOption) IRoutes {
+	return g.Handle(http.MethodOptions, path, handler, options...)
 }
 
-func (g *routerGroup) HEAD(path string, handler interface{}, doc ...*docs.Endpoint) IRoutes {
-	return g.Handle(http.MethodHead, path, handler, doc...)
+func (g *routerGroup) HEAD(path string, handler interface{}, options ...RouteOption) IRoutes {
+	return g.Handle(http.MethodHead, path, handler, options...)
 }
 
-func (g *routerGroup) GET(path string, handler interface{}, doc ...*docs.Endpoint) IRoutes {
-	return g.Handle(http.MethodGet, path, handler, doc...)
+func (g *routerGroup) GET(path string, handler interface{}, options ...RouteOption) IRoutes {
+	return g.Handle(http.MethodGet, path, handler, options...)
 }
 
-func (g *routerGroup) POST(path string, handler interface{}, doc ...*docs.Endpoint) IRoutes {
-	return g.Handle(http.MethodPost, path, handler, doc...)
+func (g *routerGroup) POST(path string, handler interface{}, options ...RouteOption) IRoutes {
+	return g.Handle(http.MethodPost, path, handler, options...)
 }
 
-func (g *routerGroup) Handle(method string, path string, handler interface{}, doc ...*docs.Endpoint) IRoutes {
-	wrapper := WrapHandler(method, g.fullPath(path), g.middlewares, g.Docs, handler, g.errorHandlers, doc...)
+func (g *routerGroup) Handle(method string, path string, handler interface{}, options ...RouteOption) IRoutes {
+	wrapper := WrapHandler(method, g.fullPath(path), g.middlewares, g.Docs, g.doc, handler, g.errorHandlers, g.mediaTypes, g.renderers, g.validate, options...)
 	g.rawRouter.Handle(method, path, wrapper.requestHandler)
 	return g
 }
 
+// MediaTypes sets the content types consumed and produced by the routes registered later in this group.
+// The request body is decoded according to its `Content-Type` and the response is encoded according to the `Accept` header.
+// The first type is the default one, used when the request doesn't specify a format. Request bodies of other content types
+// are rejected with 415, while responses fall back to the default type if the `Accept` header lists only unsupported ones.
+// Supported types are: JSON, XML, YAML, MessagePack and protobuf (the last one for proto.Message types only).
+func (g *routerGroup) MediaTypes(contentTypes ...string) IRoutes {
+	g.mediaTypes = newMediaTypes(contentTypes...)
+	return g
+}
+
+// Renderer registers a custom renderer for the routes registered later in this group.
+// If response types are given (as example values, e.g. `Report{}`), the renderer is used only for responses of those types
+// and it becomes their default format. Otherwise, it is offered for all the responses and selected only by the `Accept` header.
+// Renderers registered later take precedence, also over built-in media types with the same content type.
+func (g *routerGroup) Renderer(renderer Renderer, responseTypes ...interface{}) IRoutes {
+	g.renderers = append(g.renderers, newTypedRenderer(renderer, responseTypes))
+	return g
+}
+
 func (g *routerGroup) Use(middleware Middleware) IRoutes {
 	g.middlewares = append(g.middlewares, &middleware)
 	return g
 }
 
-func (g *routerGroup) Group(prefix string, _ ...*docs.Endpoint) IRouter {
+// Group creates a new router group with the given path prefix. The routes of the group inherit middlewares,
+// error handlers, media types and renderers of this group.
+// The optional endpoint is the documentation applied to every route of the group (and its subgroups),
+// e.g. common tags, security requirements or error responses. See docs.Endpoint.WithDefaults for the merge rules.
+func (g *routerGroup) Group(prefix string, doc ...*docs.Endpoint) IRouter {
+	if len(doc) > 1 {
+		panic("ambiguous group documentation: only one *docs.Endpoint is allowed")
+	}
+	groupDoc := g.doc
+	if len(doc) == 1 {
+		groupDoc = doc[0].WithDefaults(g.doc)
+	}
+
 	return &routerGroup{
 		pathPrefix:    g.fullPath(prefix),
 		rawRouter:     g.rawRouter.Group(prefix),
 		middlewares:   g.middlewares.copy(),
 		Docs:          g.Docs,
 		errorHandlers: g.errorHandlers.copy(),
+		mediaTypes:    g.mediaTypes,
+		renderers:     g.renderers.copy(),
+		validate:      g.validate,
+		doc:           groupDoc,
 	}
 }
 
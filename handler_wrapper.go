@@ -4,13 +4,15 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/meteran/gnext/docs"
 	"net/http"
 	"reflect"
 	"regexp"
+	"strings"
 )
 
-var paramRegExp = regexp.MustCompile(":[a-zA-Z0-9]+/")
+var paramRegExp = regexp.MustCompile("/([:*])([^/]+)")
 
 type handlerType string
 
@@ -26,16 +28,25 @@ func WrapHandler(
 	path string,
 	middlewares middlewares,
 	documentation *docs.Docs,
+	groupDoc *docs.Endpoint,
 	handler interface{},
 	errorHandlers errorHandlers,
-	doc ...*docs.Endpoint,
+	mediaTypes mediaTypes,
+	renderers renderers,
+	validate *validator.Validate,
+	options ...RouteOption,
 ) *HandlerWrapper {
+	routeOptions := newRouteOptions(options)
+
 	wrapper := &HandlerWrapper{
 		method:              method,
 		path:                path,
-		middlewares:         middlewares,
+		middlewares:         routeOptions.routeMiddlewares(middlewares),
 		originalHandler:     handler,
 		errorHandlers:       errorHandlers,
+		mediaTypes:          mediaTypes,
+		renderers:           append(renderers.copy(), routeOptions.renderers...),
+		validate:            validate,
 		errorHandlerCallers: make(map[reflect.Type]*errorHandlerCaller, len(errorHandlers)),
 		docs:                documentation,
 		params:              newParameters(path),
@@ -43,11 +54,12 @@ func WrapHandler(
 		defaultStatus:       200,
 	}
 
-	if len(doc) == 0 {
+	if routeOptions.doc == nil {
 		wrapper.doc = &docs.Endpoint{}
 	} else {
-		wrapper.doc = doc[0]
+		wrapper.doc = routeOptions.doc
 	}
+	wrapper.doc = wrapper.doc.WithDefaults(groupDoc)
 
 	wrapper.init()
 	if wrapper.documentedRouter() {
@@ -57,21 +69,39 @@ func WrapHandler(
 }
 
 func newParameters(path string) pathParameters {
-	return pathParameters{
-		paramNames: paramRegExp.FindAll([]byte(path), -1),
+	var params pathParameters
+	for _, match := range paramRegExp.FindAllStringSubmatch(path, -1) {
+		params = append(params, pathParameter{
+			name:     match[2],
+			catchAll: match[1] == "*",
+		})
 	}
+	return params
 }
 
-type pathParameters struct {
-	paramNames [][]byte
+// pathParameter is a named (`:name`) or catch-all (`*name`) segment of the path.
+type pathParameter struct {
+	name     string
+	catchAll bool
 }
 
-func (p *pathParameters) index(index int) string {
-	if index >= len(p.paramNames) {
-		panic(fmt.Sprintf("path parameter index out of range: %d", len(p.paramNames)))
+// value returns the raw value of the parameter.
+// Catch-all values are returned without the leading slash, so they can be parsed like any other parameter.
+func (p pathParameter) value(ctx *gin.Context) string {
+	value := ctx.Param(p.name)
+	if p.catchAll {
+		value = strings.TrimPrefix(value, "/")
+	}
+	return value
+}
+
+type pathParameters []pathParameter
+
+func (p pathParameters) index(index int) pathParameter {
+	if index >= len(p) {
+		panic(fmt.Sprintf("path parameter index out of range: handler expects at least %d path parameters, but the path has %d", index+1, len(p)))
 	}
-	paramName := string(p.paramNames[index])
-	return paramName[1 : len(paramName)-1]
+	return p[index]
 }
 
 type HandlerWrapper struct {
@@ -81,12 +111,19 @@ type HandlerWrapper struct {
 	pathParams          []reflect.Value
 	errorHandlers       errorHandlers
 	errorHandlerCallers map[reflect.Type]*errorHandlerCaller
+	mediaTypes          mediaTypes
+	renderers           renderers
+	validate            *validator.Validate
 	valuesNum           int
 	valuesTypes         map[reflect.Type]int
 	queryType           reflect.Type
 	bodyType            reflect.Type
+	bodyContentTypes    []string
 	headerTypes         []reflect.Type
+	cookiesTypes        []reflect.Type
+	pathTypes           []reflect.Type
 	responseType        reflect.Type
+	streamItemType      reflect.Type
 	docs                *docs.Docs
 	doc                 *docs.Endpoint
 	method              string
@@ -96,6 +133,16 @@ type HandlerWrapper struct {
 	responseIndexes     []int
 	defaultStatus       Status
 	errorResponseTypes  []reflect.Type
+	responseHeaders     []responseHeadersType
+	// middlewareResponseTypes are the responses returned from before-middlewares, which stop the request handling
+	middlewareResponseTypes []reflect.Type
+}
+
+// responseHeadersType is the type of typed response headers, returned from a handler with the given response type.
+type responseHeadersType struct {
+	headersType  reflect.Type
+	responseType reflect.Type
+	errorHandler bool
 }
 
 func (w *HandlerWrapper) documentedRouter() bool {
@@ -157,16 +204,16 @@ func (w *HandlerWrapper) inspectInParams(handlerType reflect.Type, caller *handl
 		arg := handlerType.In(i)
 
 		switch {
-		case w.isPathParam(arg):
+		case typesEqual(statusType, arg):
+			caller.addBuilder(statusBuilder(isPtr(arg)))
+			continue
+		case w.isPathParam(arg, paramIndex):
 			w.addPathParamBuilder(caller, arg, paramIndex)
 			if w.documentedRouter() {
-				w.doc.AddPathParam(w.params.index(paramIndex), arg)
+				w.doc.AddPathParam(w.docs.Schemas, w.params.index(paramIndex).name, arg)
 			}
 			paramIndex++
 
-			continue
-		case typesEqual(statusType, arg):
-			caller.addBuilder(statusBuilder(isPtr(arg)))
 			continue
 		}
 
@@ -185,23 +232,35 @@ func (w *HandlerWrapper) inspectInParams(handlerType reflect.Type, caller *handl
 		switch {
 		case arg == rawContextType:
 			caller.addBuilder(cached(rawContextBuilder, w.valuesNum))
+		case arg.Implements(multipartInterfaceType):
+			w.setBodyType(arg, binding.MIMEMultipartPOSTForm)
+			caller.addBuilder(cached(bindingBuilder(arg, multipartBinding, w.validate), w.valuesNum))
+		case arg.Implements(formInterfaceType):
+			w.setBodyType(arg, binding.MIMEPOSTForm)
+			w.addGenericBuilder(caller, arg, formBinding{})
 		case arg.Implements(bodyInterfaceType):
-			w.setBodyType(arg)
-			w.addGenericBuilder(caller, arg, binding.JSON)
+			w.setBodyType(arg, w.mediaTypes.forType(arg)...)
+			caller.addBuilder(cached(negotiatedBodyBuilder(arg, w.mediaTypes, w.validate), w.valuesNum))
+		case arg.Implements(pathInterfaceType):
+			w.appendPathType(arg)
+			caller.addBuilder(cached(uriBuilder(arg, w.params, w.validate), w.valuesNum))
 		case arg.Implements(queryInterfaceType):
 			w.setQueryType(arg)
-			w.addGenericBuilder(caller, arg, binding.Query)
+			w.addGenericBuilder(caller, arg, queryBinding{})
 		case arg.Implements(headersInterfaceType):
 			w.appendHeadersType(arg)
-			w.addGenericBuilder(caller, arg, binding.Header)
+			w.addGenericBuilder(caller, arg, headerBinding{})
+		case arg.Implements(cookiesInterfaceType):
+			w.appendCookiesType(arg)
+			w.addGenericBuilder(caller, arg, cookieBinding{})
 		default:
 			switch w.method {
 			case http.MethodGet, http.MethodDelete, http.MethodHead, http.MethodOptions:
 				w.setQueryType(arg)
-				w.addGenericBuilder(caller, arg, binding.Query)
+				w.addGenericBuilder(caller, arg, queryBinding{})
 			case http.MethodPost, http.MethodPatch, http.MethodPut:
-				w.setBodyType(arg)
-				w.addGenericBuilder(caller, arg, binding.JSON)
+				w.setBodyType(arg, w.mediaTypes.forType(arg)...)
+				caller.addBuilder(cached(negotiatedBodyBuilder(arg, w.mediaTypes, w.validate), w.valuesNum))
 			default:
 				panic("unknown input parameter purpose or type; allowed values are: request body, query and path params, headers or one of the types returned from previous middlewares")
 			}
@@ -218,17 +277,31 @@ type producingCaller interface {
 
 func (w *HandlerWrapper) inspectOutParams(handlerType reflect.Type, caller producingCaller, hType handlerType) reflect.Type {
 	var responseType reflect.Type
+	var headersTypes []reflect.Type
 	for i := 0; i < handlerType.NumOut(); i++ {
 		arg := handlerType.Out(i)
 
 		// response parameters that shouldn't be in cache
 		switch {
+		case arg.Implements(responseHeadersInterfaceType):
+			headersTypes = append(headersTypes, arg)
+			caller.addSetter(responseHeadersSetter)
+			continue
 		case typesEqual(headersType, arg):
 			caller.addSetter(headersSetter(isPtr(arg)))
 			continue
+		case typesEqual(cookiesType, arg):
+			caller.addSetter(cookiesSetter(isPtr(arg)))
+			continue
 		case typesEqual(statusType, arg):
 			caller.addSetter(statusSetter(isPtr(arg)))
 			continue
+		case typesEqual(abortType, arg):
+			if hType != htBeforeMiddleware {
+				panic("only before-middleware can return Abort")
+			}
+			caller.addSetter(abortSetter(isPtr(arg)))
+			continue
 		case arg.Implements(errorInterfaceType):
 			if hType == htAfterMiddleware {
 				panic("after-middleware can not return error")
@@ -238,22 +311,51 @@ func (w *HandlerWrapper) inspectOutParams(handlerType reflect.Type, caller produ
 		}
 
 		if index, exists := w.valuesTypes[arg]; exists {
-			if w.isResponseIndex(index) {
-				caller.addSetter(responseSetter(index))
-				responseType = arg
-			} else {
+			if !w.isResponseIndex(index) {
 				caller.addSetter(valueSetter(index))
+				continue
 			}
+
+			switch {
+			case hType == htBeforeMiddleware && isNilable(arg.Kind()):
+				caller.addSetter(abortingResponseSetter(index, Status(docs.DefaultStatus(arg))))
+			case hType == htTargetHandler:
+				// the response type may be returned by a before-middleware first
+				if w.responseType == nil {
+					w.setResponseType(arg)
+				}
+				caller.addSetter(targetResponseSetter(arg, index))
+			default:
+				caller.addSetter(responseSetter(index))
+			}
+			responseType = arg
+			continue
+		}
+
+		if itemType, isStream := streamItemType(arg); isStream && hType == htTargetHandler {
+			w.setStreamItemType(itemType)
+			// an iterator may set the status of the stream with the `default_status` tag, like a response
+			w.defaultStatus = Status(docs.DefaultStatus(arg))
+			caller.addSetter(streamSetter(w.valuesNum))
+			w.valuesTypes[arg] = w.valuesNum
+			w.valuesNum++
 			continue
 		}
 
 		switch {
+		// a response returned from a before-middleware stops the request handling, when it is not nil;
+		// a response returned by value is passed to the next handlers, as it can't be nil
+		case arg.Implements(responseInterfaceType) && hType == htBeforeMiddleware && isNilable(arg.Kind()):
+			w.middlewareResponseTypes = append(w.middlewareResponseTypes, arg)
+			caller.addSetter(abortingResponseSetter(w.valuesNum, Status(docs.DefaultStatus(arg))))
+			w.responseIndexes = append(w.responseIndexes, w.valuesNum)
+			responseType = arg
 		// if this is a target handler
 		// we consider any unknown returned object as a response
 		// just for developer convenience
 		case arg.Implements(responseInterfaceType) || hType == htTargetHandler:
 			w.setResponseType(arg)
-			caller.addSetter(responseSetter(w.valuesNum))
+			caller.addSetter(targetResponseSetter(arg, w.valuesNum))
 			w.responseIndexes = append(w.responseIndexes, w.valuesNum)
 			responseType = arg
 		case hType == htErrorHandler:
@@ -268,20 +370,46 @@ func (w *HandlerWrapper) inspectOutParams(handlerType reflect.Type, caller produ
 		w.valuesTypes[arg] = w.valuesNum
 		w.valuesNum++
 	}
+
+	for _, headersType := range headersTypes {
+		w.responseHeaders = append(w.responseHeaders, responseHeadersType{
+			headersType:  headersType,
+			responseType: responseType,
+			errorHandler: hType == htErrorHandler,
+		})
+	}
 	return responseType
 }
 
+func (w *HandlerWrapper) setStreamItemType(itemType reflect.Type) {
+	if w.streamItemType != nil || w.responseType != nil {
+		panic(fmt.Sprintf("ambiguous response type: stream of %s", itemType))
+	}
+	w.streamItemType = itemType
+}
+
 func (w *HandlerWrapper) appendHeadersType(argType reflect.Type) {
 	if argType.Kind() != reflect.Map {
 		w.headerTypes = append(w.headerTypes, argType)
 	}
 }
 
-func (w *HandlerWrapper) setBodyType(argType reflect.Type) {
+func (w *HandlerWrapper) appendCookiesType(argType reflect.Type) {
+	if argType.Kind() != reflect.Slice {
+		w.cookiesTypes = append(w.cookiesTypes, argType)
+	}
+}
+
+func (w *HandlerWrapper) appendPathType(argType reflect.Type) {
+	w.pathTypes = append(w.pathTypes, argType)
+}
+
+func (w *HandlerWrapper) setBodyType(argType reflect.Type, contentTypes ...string) {
 	if w.bodyType != nil {
 		panic(fmt.Sprintf("ambiguous body type: %s and %s", w.bodyType, argType))
 	}
 	w.bodyType = argType
+	w.bodyContentTypes = contentTypes
 }
 
 func (w *HandlerWrapper) setQueryType(argType reflect.Type) {
@@ -292,17 +420,28 @@ func (w *HandlerWrapper) setQueryType(argType reflect.Type) {
 }
 
 func (w *HandlerWrapper) setResponseType(argType reflect.Type) {
-	if w.responseType != nil {
+	if w.responseType != nil || w.streamItemType != nil {
 		panic(fmt.Sprintf("ambiguous response type: %s and %s", w.responseType, argType))
 	}
 	w.responseType = argType
 }
 
-func (w *HandlerWrapper) isPathParam(argType reflect.Type) bool {
+// isPathParam reports whether the argument should be bound from the url.
+// Scalar types and types implementing encoding.TextUnmarshaler are path params, unless they are request markers.
+// Values of user-defined types returned by previous handlers in the chain take precedence over path params.
+// Path params are matched by position, so while the route has path params left, they take precedence
+// over values of builtin types, like string, returned by previous handlers.
+func (w *HandlerWrapper) isPathParam(argType reflect.Type, paramIndex int) bool {
+	if isRequestMarker(argType) {
+		return false
+	}
+	if _, exists := w.valuesTypes[argType]; exists && (paramIndex >= len(w.params) || !isBuiltinType(argType)) {
+		return false
+	}
 	if isPtr(argType) {
 		argType = argType.Elem()
 	}
-	return argType == intType || argType == stringType
+	return isScalarKind(argType.Kind()) || reflect.PtrTo(argType).Implements(textUnmarshalerType)
 }
 
 func (w *HandlerWrapper) addPathParamBuilder(caller *handlerCaller, argType reflect.Type, paramIndex int) {
@@ -311,37 +450,70 @@ func (w *HandlerWrapper) addPathParamBuilder(caller *handlerCaller, argType refl
 		optional = true
 		argType = argType.Elem()
 	}
-	caller.addBuilder(paramBuilder(argType.Kind(), w.params.index(paramIndex), optional))
+	caller.addBuilder(paramBuilder(argType, w.params.index(paramIndex), optional))
 }
 
 func (w *HandlerWrapper) addGenericBuilder(caller *handlerCaller, argType reflect.Type, bindType binding.Binding) {
-	caller.addBuilder(cached(genericBuilder(argType, bindType), w.valuesNum))
+	caller.addBuilder(cached(genericBuilder(argType, bindType, w.validate), w.valuesNum))
 }
 
 func (w *HandlerWrapper) fillDocumentation() {
 	w.doc.SetTagsFromPath(w.path)
 
 	if w.bodyType != nil {
-		w.doc.SetBodyType(w.bodyType)
+		w.doc.SetBodyType(w.docs.Schemas, w.bodyType, w.bodyContentTypes...)
 	}
 
 	for _, errorType := range w.errorResponseTypes {
-		w.doc.AddErrorResponse(errorType)
+		w.doc.AddErrorResponse(w.docs.Schemas, errorType, w.contentTypes(errorType)...)
+	}
+
+	for _, responseType := range w.middlewareResponseTypes {
+		w.doc.AddResponse(w.docs.Schemas, responseType, w.contentTypes(responseType)...)
 	}
 
 	if w.responseType != nil {
-		w.doc.AddResponse(w.responseType)
+		w.doc.AddResponse(w.docs.Schemas, w.responseType, w.contentTypes(w.responseType)...)
 		w.defaultStatus = Status(docs.DefaultStatus(w.responseType))
 	}
 
+	if w.streamItemType != nil {
+		w.doc.AddStreamResponse(w.docs.Schemas, w.streamItemType, int(w.defaultStatus), streamContentTypes...)
+	}
+
+	for _, headers := range w.responseHeaders {
+		if headers.errorHandler {
+			w.doc.AddResponseHeadersType(w.docs.Schemas, headers.headersType, headers.responseType, 500)
+		} else {
+			w.doc.AddResponseHeadersType(w.docs.Schemas, headers.headersType, w.responseType, int(w.defaultStatus))
+		}
+	}
+
 	if w.queryType != nil {
-		w.doc.SetQueryType(w.queryType)
+		w.doc.SetQueryType(w.docs.Schemas, w.queryType)
 	}
 
 	for _, headerType := range w.headerTypes {
-		w.doc.AddHeadersType(headerType)
+		w.doc.AddHeadersType(w.docs.Schemas, headerType)
 	}
 
+	for _, cookiesType := range w.cookiesTypes {
+		w.doc.AddCookiesType(w.docs.Schemas, cookiesType)
+	}
+
+	for _, pathType := range w.pathTypes {
+		w.doc.AddPathType(w.docs.Schemas, pathType)
+	}
+
+	var securitySchemes []*docs.SecurityScheme
+	for _, middleware := range w.middlewares {
+		if middleware.Security != nil {
+			w.docs.AddSecurityScheme(middleware.Security)
+			securitySchemes = append(securitySchemes, middleware.Security)
+		}
+	}
+	w.doc.AddSecurity(securitySchemes...)
+
 	w.docs.SetPath(w.path, w.method, w.doc)
 }
 
@@ -352,9 +524,14 @@ func (w *HandlerWrapper) requestHandler(rawContext *gin.Context) {
 		status:        w.defaultStatus,
 		responseIndex: -1,
 	}
+	defer context.closeFile()
 
 	for i := 0; i < len(w.handlersChain); {
 		w.handlersChain[i].call(context)
+		if context.stream != nil && context.error != nil {
+			releaseStream(*context.stream)
+			context.stream = nil
+		}
 		if context.error != nil {
 			errType := context.error.Type()
 			if errType == errorInterfaceType {
@@ -370,16 +547,31 @@ func (w *HandlerWrapper) requestHandler(rawContext *gin.Context) {
 			if i < 0 {
 				break
 			}
+		} else if context.aborted {
+			context.aborted = false
+			i = w.handlerFallbacks[i]
+			if i < 0 {
+				break
+			}
 		} else {
+			if context.stream != nil {
+				w.stream(context, *context.stream)
+				context.stream = nil
+				context.streamed = true
+			}
 			i++
 		}
 	}
 
+	if context.streamed {
+		return
+	}
+
 	if context.responseIndex < 0 {
 		rawContext.AbortWithStatus(int(context.status))
 		return
 	}
-	rawContext.JSON(int(context.status), context.values[context.responseIndex].Interface())
+	w.render(rawContext, int(context.status), *context.values[context.responseIndex])
 }
 
 func (w *HandlerWrapper) wrapErrorHandlers() {
@@ -0,0 +1,140 @@
+package gnext
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type requestHeaders struct {
+	Headers
+	RequestId string `header:"X-Request-Id" binding:"required,uuid"`
+	Retries   int    `header:"X-Retries,default=1" binding:"max=3"`
+}
+
+type rateLimitHeaders struct {
+	ResponseHeaders
+	Remaining int       `header:"X-RateLimit-Remaining" doc:"requests left in the window"`
+	Reset     time.Time `header:"X-RateLimit-Reset"`
+}
+
+type cacheHeaders struct {
+	ResponseHeaders `status_codes:"200,304"`
+	ETag            string   `header:"ETag"`
+	Vary            []string `header:"Vary"`
+	MaxAge          *int     `header:"X-Max-Age"`
+}
+
+type notFoundResponse struct {
+	ErrorResponse `default_status:"404"`
+	Message       string `json:"message"`
+}
+
+type retryHeaders struct {
+	ResponseHeaders
+	RetryAfter int `header:"Retry-After"`
+}
+
+type failingHeader struct{}
+
+func (f failingHeader) MarshalText() ([]byte, error) {
+	return nil, fmt.Errorf("failed")
+}
+
+func TestInvalidTypedHeader(t *testing.T) {
+	r := Router()
+	r.GET("/retries", func(headers *requestHeaders) int {
+		return headers.Retries
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/retries",
+		withHeader("X-Request-Id", "5e0c2b6a-4b8f-4d6e-9a53-0f1e2d3c4b5a"), withHeader("X-Retries", "2"))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `2`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/retries",
+		withHeader("X-Request-Id", "5e0c2b6a-4b8f-4d6e-9a53-0f1e2d3c4b5a"), withHeader("X-Retries", "many"))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestResponseHeaders(t *testing.T) {
+	reset := time.Date(2023, 5, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	r := Router()
+	r.Use(Middleware{
+		Before: func() *rateLimitHeaders {
+			return &rateLimitHeaders{Remaining: 99, Reset: reset}
+		},
+	})
+	r.GET("/items", func() (string, cacheHeaders) {
+		return "items", cacheHeaders{ETag: `"v1"`, Vary: []string{"Accept", "Accept-Encoding"}}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/items")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "99", response.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "Mon, 01 May 2023 11:00:00 GMT", response.Header().Get("X-RateLimit-Reset"))
+	assert.Equal(t, `"v1"`, response.Header().Get("ETag"))
+	assert.Equal(t, []string{"Accept", "Accept-Encoding"}, response.Header().Values("Vary"))
+	assert.NotContains(t, response.Header(), "X-Max-Age")
+}
+
+func TestResponseHeadersFormatError(t *testing.T) {
+	type headers struct {
+		ResponseHeaders
+		Value failingHeader `header:"X-Value"`
+	}
+
+	r := Router()
+	r.GET("/items", func() (string, *headers) {
+		return "items", &headers{}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/items")
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Empty(t, response.Header().Get("X-Value"))
+}
+
+func TestHeadersDocs(t *testing.T) {
+	r := Router()
+	r.OnError(func(err *NotFound) (*notFoundResponse, retryHeaders) { return nil, retryHeaders{} })
+	r.GET("/items", func(headers *requestHeaders) (string, *cacheHeaders, *rateLimitHeaders) { return "", nil, nil })
+
+	doc := generateDocs(t, r)
+	operation := doc.Paths["/items"].Get
+
+	requestId := operation.Parameters.GetByInAndName("header", "X-Request-Id")
+	require.NotNil(t, requestId)
+	assert.True(t, requestId.Required)
+	assert.Equal(t, "uuid", requestId.Schema.Value.Format)
+
+	retries := operation.Parameters.GetByInAndName("header", "X-Retries")
+	require.NotNil(t, retries)
+	assert.False(t, retries.Required)
+	assert.Equal(t, "integer", retries.Schema.Value.Type)
+	assert.Equal(t, 3.0, *retries.Schema.Value.Max)
+	assert.Equal(t, 1.0, retries.Schema.Value.Default)
+
+	ok := operation.Responses.Get(200).Value
+	assert.Len(t, ok.Headers, 5)
+	assert.NotNil(t, ok.Content.Get("application/json"))
+	remaining := ok.Headers["X-RateLimit-Remaining"].Value
+	assert.True(t, remaining.Required)
+	assert.Equal(t, "integer", remaining.Schema.Value.Type)
+	assert.Equal(t, "requests left in the window", remaining.Description)
+	assert.Equal(t, "date-time", ok.Headers["X-RateLimit-Reset"].Value.Schema.Value.Format)
+	assert.Equal(t, "array", ok.Headers["Vary"].Value.Schema.Value.Type)
+	assert.False(t, ok.Headers["X-Max-Age"].Value.Required)
+
+	notModified := operation.Responses.Get(304).Value
+	assert.Len(t, notModified.Headers, 3)
+	assert.Contains(t, notModified.Headers, "ETag")
+
+	notFound := operation.Responses.Get(404).Value
+	assert.Len(t, notFound.Headers, 1)
+	assert.Contains(t, notFound.Headers, "Retry-After")
+	assert.Empty(t, operation.Responses.Get(500).Value.Headers)
+}
@@ -0,0 +1,228 @@
+package gnext
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// JWTClaims are the registered claims of a JSON Web Token, see RFC 7519.
+// Embed them in your own struct to receive custom claims from BearerAuth.
+type JWTClaims struct {
+	Issuer    string      `json:"iss,omitempty"`
+	Subject   string      `json:"sub,omitempty"`
+	Audience  JWTAudience `json:"aud,omitempty"`
+	ExpiresAt int64       `json:"exp,omitempty"`
+	NotBefore int64       `json:"nbf,omitempty"`
+	IssuedAt  int64       `json:"iat,omitempty"`
+	ID        string      `json:"jti,omitempty"`
+}
+
+// JWTAudience is the `aud` claim, sent either as a single string or an array of strings.
+type JWTAudience []string
+
+func (a *JWTAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = JWTAudience{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+func (a JWTAudience) contains(audience string) bool {
+	for _, value := range a {
+		if value == audience {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	errMalformedToken   = errors.New("malformed token")
+	errInvalidSignature = errors.New("invalid signature")
+	errUnknownKey       = errors.New("unknown key")
+	errInvalidToken     = errors.New("invalid token")
+)
+
+var jwtHashes = map[string]crypto.Hash{
+	"256": crypto.SHA256,
+	"384": crypto.SHA384,
+	"512": crypto.SHA512,
+}
+
+// ecdsaCurveSizes maps the sizes of the hashes used by ES* algorithms to the bit sizes of the matching curves.
+var ecdsaCurveSizes = map[string]int{
+	"256": 256,
+	"384": 384,
+	"512": 521,
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyId     string `json:"kid"`
+}
+
+// jwtVerifier verifies the signatures and the registered claims of JSON Web Tokens, using locally configured keys.
+type jwtVerifier struct {
+	key           interface{}
+	keys          map[string]interface{}
+	issuer        string
+	audience      string
+	leeway        time.Duration
+	requireExpiry bool
+	now           func() time.Time
+}
+
+// verify checks the token and decodes its payload into the claims.
+func (v *jwtVerifier) verify(token string, claims interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errMalformedToken
+	}
+
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return err
+	}
+	key := v.key
+	if header.KeyId != "" && v.keys != nil {
+		key = v.keys[header.KeyId]
+	}
+	if key == nil {
+		return errUnknownKey
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errMalformedToken
+	}
+	if err = verifyJWTSignature(header.Algorithm, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return err
+	}
+
+	var registered JWTClaims
+	if err = decodeJWTPart(parts[1], &registered); err != nil {
+		return err
+	}
+	if err = v.validateClaims(&registered); err != nil {
+		return err
+	}
+	return decodeJWTPart(parts[1], claims)
+}
+
+func (v *jwtVerifier) validateClaims(claims *JWTClaims) error {
+	now := v.now()
+	if claims.ExpiresAt == 0 {
+		if v.requireExpiry {
+			return errors.New("token has no expiry")
+		}
+	} else if now.After(time.Unix(claims.ExpiresAt, 0).Add(v.leeway)) {
+		return errors.New("token is expired")
+	}
+	if claims.NotBefore != 0 && now.Before(time.Unix(claims.NotBefore, 0).Add(-v.leeway)) {
+		return errors.New("token is not valid yet")
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return errors.New("invalid issuer")
+	}
+	if v.audience != "" && !claims.Audience.contains(v.audience) {
+		return errors.New("invalid audience")
+	}
+	return nil
+}
+
+func decodeJWTPart(part string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errMalformedToken
+	}
+	if err = json.Unmarshal(data, value); err != nil {
+		return errMalformedToken
+	}
+	return nil
+}
+
+// verifyJWTSignature checks the signature using the algorithm from the token header.
+// The algorithm must match the type of the key, so e.g. a public RSA key can't be used as an HMAC secret.
+func verifyJWTSignature(algorithm string, key interface{}, input []byte, signature []byte) error {
+	if algorithm == "EdDSA" {
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("key does not match the %s algorithm", algorithm)
+		}
+		if !ed25519.Verify(publicKey, input, signature) {
+			return errInvalidSignature
+		}
+		return nil
+	}
+
+	if len(algorithm) != 5 {
+		return fmt.Errorf("unsupported algorithm: %q", algorithm)
+	}
+	hash, supported := jwtHashes[algorithm[2:]]
+	if !supported {
+		return fmt.Errorf("unsupported algorithm: %q", algorithm)
+	}
+	digest := hash.New()
+	digest.Write(input)
+
+	var valid bool
+	switch key := key.(type) {
+	case []byte:
+		if algorithm[:2] != "HS" {
+			return fmt.Errorf("key does not match the %s algorithm", algorithm)
+		}
+		mac := hmac.New(hash.New, key)
+		mac.Write(input)
+		valid = hmac.Equal(signature, mac.Sum(nil))
+	case *rsa.PublicKey:
+		switch algorithm[:2] {
+		case "RS":
+			valid = rsa.VerifyPKCS1v15(key, hash, digest.Sum(nil), signature) == nil
+		case "PS":
+			valid = rsa.VerifyPSS(key, hash, digest.Sum(nil), signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		default:
+			return fmt.Errorf("key does not match the %s algorithm", algorithm)
+		}
+	case *ecdsa.PublicKey:
+		if algorithm[:2] != "ES" || key.Curve.Params().BitSize != ecdsaCurveSizes[algorithm[2:]] {
+			return fmt.Errorf("key does not match the %s algorithm", algorithm)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errInvalidSignature
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		valid = ecdsa.Verify(key, digest.Sum(nil), r, s)
+	default:
+		return fmt.Errorf("key does not match the %s algorithm", algorithm)
+	}
+
+	if !valid {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+// validateJWTKey panics if the key is not supported by verifyJWTSignature.
+func validateJWTKey(key interface{}) {
+	switch key.(type) {
+	case []byte, *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+	default:
+		panic(fmt.Sprintf("unsupported JWT key type: %T, use []byte, *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey", key))
+	}
+}
@@ -0,0 +1,133 @@
+package gnext
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+)
+
+var (
+	protoMessageType   = reflect.TypeOf((*proto.Message)(nil)).Elem()
+	xmlMarshalerType   = reflect.TypeOf((*xml.Marshaler)(nil)).Elem()
+	xmlUnmarshalerType = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+)
+
+// mediaType describes how to decode a request body and encode a response in the given format.
+type mediaType struct {
+	binding binding.Binding
+	render  func(ctx *gin.Context, status int, obj interface{})
+	// accepts tells if the value of given type can be encoded or decoded, nil means any type
+	accepts func(reflect.Type) bool
+}
+
+var knownMediaTypes = map[string]mediaType{
+	binding.MIMEJSON:     {binding: jsonBinding{}, render: (*gin.Context).JSON},
+	binding.MIMEXML:      {binding: xmlBinding{}, render: (*gin.Context).XML, accepts: isXMLSupported},
+	binding.MIMEXML2:     {binding: xmlBinding{}, render: (*gin.Context).XML, accepts: isXMLSupported},
+	binding.MIMEYAML:     {binding: yamlBinding{}, render: (*gin.Context).YAML},
+	binding.MIMEMSGPACK:  {binding: msgPackBinding{}, render: renderMsgPack},
+	binding.MIMEMSGPACK2: {binding: msgPackBinding{}, render: renderMsgPack},
+	binding.MIMEPROTOBUF: {
+		binding: protoBufBinding{},
+		render:  (*gin.Context).ProtoBuf,
+		accepts: func(t reflect.Type) bool { return t.Implements(protoMessageType) },
+	},
+}
+
+// isXMLSupported reports whether encoding/xml can handle values of the given type. It can't handle maps, channels,
+// functions and complex numbers, unless they implement xml.Marshaler or xml.Unmarshaler, like gin.H does.
+// Interfaces are accepted, as their values are known only at runtime.
+func isXMLSupported(t reflect.Type) bool {
+	return xmlSupported(t, map[reflect.Type]bool{})
+}
+
+func xmlSupported(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return true
+	}
+	visited[t] = true
+
+	if t.Implements(xmlMarshalerType) || reflect.PtrTo(t).Implements(xmlMarshalerType) ||
+		t.Implements(xmlUnmarshalerType) || reflect.PtrTo(t).Implements(xmlUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Map, reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return false
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return xmlSupported(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if (field.PkgPath != "" && !field.Anonymous) || field.Tag.Get("xml") == "-" {
+				continue
+			}
+			if !xmlSupported(field.Type, visited) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func renderMsgPack(ctx *gin.Context, status int, obj interface{}) {
+	ctx.Render(status, render.MsgPack{Data: obj})
+}
+
+// mediaTypes is a list of content types that routes consume and produce, the first one is the default.
+type mediaTypes []string
+
+var defaultMediaTypes = mediaTypes{binding.MIMEJSON}
+
+func newMediaTypes(contentTypes ...string) mediaTypes {
+	if len(contentTypes) == 0 {
+		panic("at least one media type is required")
+	}
+	for _, contentType := range contentTypes {
+		if _, exists := knownMediaTypes[contentType]; !exists {
+			panic(fmt.Sprintf("unsupported media type: '%s'", contentType))
+		}
+	}
+	return append(mediaTypes{}, contentTypes...)
+}
+
+// forType returns the media types able to encode and decode values of the given type.
+// If there are none, the default media type is returned.
+func (m mediaTypes) forType(t reflect.Type) []string {
+	if result := m.supporting(t); len(result) > 0 {
+		return result
+	}
+	return m[:1]
+}
+
+// supporting returns the media types able to encode and decode values of the given type.
+func (m mediaTypes) supporting(t reflect.Type) mediaTypes {
+	var result mediaTypes
+	for _, contentType := range m {
+		accepts := knownMediaTypes[contentType].accepts
+		if accepts == nil || accepts(t) {
+			result = append(result, contentType)
+		}
+	}
+	return result
+}
+
+// requestBinding returns the binding matching request `Content-Type` header.
+// Missing content type falls back to the default media type, other content types not in the list are not supported.
+func (m mediaTypes) requestBinding(ctx *gin.Context) (binding.Binding, error) {
+	contentType := ctx.ContentType()
+	if contentType == "" && len(m) > 0 {
+		return knownMediaTypes[m[0]].binding, nil
+	}
+	for _, offered := range m {
+		if offered == contentType {
+			return knownMediaTypes[offered].binding, nil
+		}
+	}
+	return nil, &UnsupportedMediaType{fmt.Errorf("unsupported content type: '%s'", contentType)}
+}
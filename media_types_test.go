@@ -0,0 +1,142 @@
+package gnext
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type negotiatedRequest struct {
+	Body
+	Name string `json:"name" xml:"name" yaml:"name" binding:"required"`
+}
+
+type negotiatedResponse struct {
+	Greeting string `json:"greeting" xml:"greeting" yaml:"greeting"`
+}
+
+func negotiatedRouter() *RootRouter {
+	r := Router()
+	r.MediaTypes(binding.MIMEJSON, binding.MIMEXML, binding.MIMEYAML)
+	r.POST("/greet", func(req *negotiatedRequest) *negotiatedResponse {
+		return &negotiatedResponse{Greeting: "hello " + req.Name}
+	})
+	return r
+}
+
+func TestContentNegotiation(t *testing.T) {
+	r := negotiatedRouter()
+
+	cases := []struct {
+		contentType     string
+		accept          string
+		body            string
+		responseType    string
+		responsePayload string
+	}{
+		{"", "", `{"name": "json"}`, binding.MIMEJSON, `{"greeting":"hello json"}`},
+		{"application/json", "application/xml", `{"name": "json"}`, binding.MIMEXML, `<negotiatedResponse><greeting>hello json</greeting></negotiatedResponse>`},
+		{"application/xml; charset=utf-8", "application/x-yaml", `<negotiatedRequest><name>xml</name></negotiatedRequest>`, binding.MIMEYAML, "greeting: hello xml\n"},
+		{"application/x-yaml", "text/html, */*", "name: yaml", binding.MIMEJSON, `{"greeting":"hello yaml"}`},
+		{"", "text/html", `{"name": "fallback"}`, binding.MIMEJSON, `{"greeting":"hello fallback"}`},
+	}
+
+	for idx, c := range cases {
+		response := makeRequest(t, r, http.MethodPost, "/greet", withHeader("Content-Type", c.contentType), withHeader("Accept", c.accept), withRawBody(c.body))
+		assert.Equalf(t, http.StatusOK, response.Code, "case: %d", idx)
+		assert.Containsf(t, response.Header().Get("Content-Type"), c.responseType, "case: %d", idx)
+		assert.Equalf(t, c.responsePayload, response.Body.String(), "case: %d", idx)
+	}
+}
+
+func TestContentNegotiationValidation(t *testing.T) {
+	r := negotiatedRouter()
+
+	response := makeRequest(t, r, http.MethodPost, "/greet", withHeader("Content-Type", "application/xml"), withHeader("Accept", "application/xml"), withRawBody(`<negotiatedRequest></negotiatedRequest>`))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Header().Get("Content-Type"), binding.MIMEXML)
+}
+
+func TestUnsupportedContentType(t *testing.T) {
+	r := negotiatedRouter()
+
+	response := makeRequest(t, r, http.MethodPost, "/greet", withHeader("Content-Type", "text/plain"), withRawBody(`{"name": "text"}`))
+	assert.Equal(t, http.StatusUnsupportedMediaType, response.Code)
+	assert.Contains(t, response.Body.String(), "unsupported content type: 'text/plain'")
+
+	r = Router()
+	r.MediaTypes(binding.MIMEJSON, binding.MIMEPROTOBUF)
+	r.POST("/greet", func(req *negotiatedRequest) *negotiatedResponse {
+		return &negotiatedResponse{Greeting: "hello " + req.Name}
+	})
+
+	response = makeRequest(t, r, http.MethodPost, "/greet", withHeader("Content-Type", binding.MIMEPROTOBUF))
+	assert.Equal(t, http.StatusUnsupportedMediaType, response.Code)
+
+	response = makeRequest(t, r, http.MethodPost, "/greet", withHeader("Content-Type", binding.MIMEJSON), withRawBody(`{"name": "json"}`))
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestContentNegotiationSkipsUnsupportedTypes(t *testing.T) {
+	type tagged struct {
+		Tags map[string]string `xml:"tags" json:"tags"`
+	}
+
+	r := Router()
+	r.MediaTypes(binding.MIMEJSON, binding.MIMEXML)
+	r.GET("/counts", func() map[string]int {
+		return map[string]int{"a": 1}
+	})
+	r.GET("/tagged", func() *tagged {
+		return &tagged{Tags: map[string]string{"a": "b"}}
+	})
+	r.GET("/h", func() gin.H {
+		return gin.H{"a": "b"}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/counts", withHeader("Accept", binding.MIMEXML))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, binding.MIMEJSON+"; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.Equal(t, `{"a":1}`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/tagged", withHeader("Accept", binding.MIMEXML))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `{"tags":{"a":"b"}}`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/h", withHeader("Accept", binding.MIMEXML))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `<map><a>b</a></map>`, response.Body.String())
+
+	doc := generateDocs(t, r)
+	content := doc.Paths["/counts"].Get.Responses.Get(200).Value.Content
+	assert.NotNil(t, content.Get(binding.MIMEJSON))
+	assert.Nil(t, content.Get(binding.MIMEXML))
+}
+
+func TestContentNegotiationDocs(t *testing.T) {
+	r := negotiatedRouter()
+
+	doc := generateDocs(t, r)
+
+	operation := doc.Paths["/greet"].Post
+	contentTypes := []string{binding.MIMEJSON, binding.MIMEXML, binding.MIMEYAML}
+	for _, contentType := range contentTypes {
+		require.NotNil(t, operation.RequestBody.Value.Content.Get(contentType), contentType)
+		require.NotNil(t, operation.Responses.Get(200).Value.Content.Get(contentType), contentType)
+	}
+	assert.Len(t, operation.RequestBody.Value.Content, 3)
+	assert.Len(t, operation.Responses.Get(200).Value.Content, 3)
+	assert.NotNil(t, operation.Responses.Get(500).Value.Content.Get(binding.MIMEXML))
+}
+
+func TestUnsupportedMediaType(t *testing.T) {
+	assert.Panics(t, func() {
+		Router().MediaTypes("text/csv")
+	})
+	assert.Panics(t, func() {
+		Router().MediaTypes()
+	})
+}
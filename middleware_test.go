@@ -2,6 +2,8 @@ package gnext
 
 import (
 	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/meteran/gnext/docs"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
@@ -256,3 +258,209 @@ func TestBeforeAndAfterMiddlewareOrdering(t *testing.T) {
 	assert.True(t, firstAfterCalled)
 	assert.True(t, secondAfterCalled)
 }
+
+func TestRouteMiddlewares(t *testing.T) {
+	var calls []string
+	recording := func(name string) Middleware {
+		return Middleware{
+			Name:   name,
+			Before: func() { calls = append(calls, "before "+name) },
+			After:  func() { calls = append(calls, "after "+name) },
+		}
+	}
+
+	r := Router()
+	r.Use(recording("logger"))
+	r.Use(recording("tenant"))
+	r.GET("/all", func() { calls = append(calls, "handler") }, WithMiddleware(recording("audit"), recording("cache")))
+	r.GET("/skipped", func() { calls = append(calls, "handler") }, WithoutMiddleware("tenant"), WithMiddleware(recording("audit")))
+	r.GET("/plain", func() { calls = append(calls, "handler") })
+
+	makeRequest(t, r, http.MethodGet, "/all")
+	assert.Equal(t, []string{
+		"before logger", "before tenant", "before audit", "before cache",
+		"handler",
+		"after cache", "after audit", "after tenant", "after logger",
+	}, calls)
+
+	calls = nil
+	makeRequest(t, r, http.MethodGet, "/skipped")
+	assert.Equal(t, []string{"before logger", "before audit", "handler", "after audit", "after logger"}, calls)
+
+	calls = nil
+	makeRequest(t, r, http.MethodGet, "/plain")
+	assert.Equal(t, []string{"before logger", "before tenant", "handler", "after tenant", "after logger"}, calls)
+
+	assert.Panics(t, func() { r.GET("/unknown", func() {}, WithoutMiddleware("unknown")) })
+	assert.Panics(t, func() { WithoutMiddleware("") })
+}
+
+func TestRouteMiddlewareValues(t *testing.T) {
+	type user struct {
+		Name string
+	}
+
+	auth := Middleware{
+		Name: "auth",
+		Before: func() (*user, error) {
+			return &user{Name: "john"}, nil
+		},
+		Security: &docs.SecurityScheme{Name: "auth", Scheme: openapi3.NewJWTSecurityScheme()},
+	}
+
+	r := Router()
+	r.GET("/me", func(u *user) string { return u.Name }, WithMiddleware(auth))
+	r.GET("/public", func() string { return "public" })
+
+	api := r.Group("/api")
+	api.Use(auth)
+	api.GET("/health", func() string { return "ok" }, WithoutMiddleware("auth"))
+
+	response := makeRequest(t, r, http.MethodGet, "/me")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"john"`, response.Body.String())
+
+	doc := generateDocs(t, r)
+	assert.Equal(t, openapi3.SecurityRequirements{{"auth": []string{}}}, *doc.Paths["/me"].Get.Security)
+	assert.Nil(t, doc.Paths["/public"].Get.Security)
+	assert.Nil(t, doc.Paths["/api/health"].Get.Security)
+}
+
+type cachedItem struct {
+	Response
+	Name string `json:"name"`
+}
+
+type rateLimited struct {
+	Response `default_status:"429"`
+	Message  string `json:"message"`
+}
+
+func TestBeforeMiddlewareShortCircuit(t *testing.T) {
+	var calls []string
+
+	type cacheQuery struct {
+		Query
+		Cached bool `form:"cached"`
+	}
+
+	r := Router()
+	r.Use(Middleware{
+		Before: func() { calls = append(calls, "before logger") },
+		After:  func() { calls = append(calls, "after logger") },
+	})
+	r.Use(Middleware{
+		Before: func(q *cacheQuery) *cachedItem {
+			calls = append(calls, "before cache")
+			if q.Cached {
+				return &cachedItem{Name: "cached"}
+			}
+			return nil
+		},
+		After: func(item *cachedItem) {
+			calls = append(calls, "after cache "+item.Name)
+		},
+	})
+	r.Use(Middleware{
+		Before: func() { calls = append(calls, "before other") },
+	})
+	r.GET("/items", func() *cachedItem {
+		calls = append(calls, "handler")
+		return &cachedItem{Name: "fresh"}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/items?cached=true")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `{"name":"cached"}`, response.Body.String())
+	assert.Equal(t, []string{"before logger", "before cache", "after cache cached", "after logger"}, calls)
+
+	calls = nil
+	response = makeRequest(t, r, http.MethodGet, "/items")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `{"name":"fresh"}`, response.Body.String())
+	assert.Equal(t, []string{"before logger", "before cache", "before other", "handler", "after cache fresh", "after logger"}, calls)
+}
+
+func TestBeforeMiddlewareValueResponse(t *testing.T) {
+	type message struct {
+		Response
+		Msg string `json:"msg"`
+	}
+
+	r := Router()
+	r.Use(Middleware{
+		Before: func() message {
+			return message{Msg: "mw"}
+		},
+	})
+	r.GET("/message", func(m message) message {
+		return message{Msg: m.Msg + "+handler"}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/message")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `{"msg":"mw+handler"}`, response.Body.String())
+}
+
+func TestBeforeMiddlewareAbort(t *testing.T) {
+	var called bool
+
+	type ifNoneMatch struct {
+		Headers
+		ETag string `header:"If-None-Match"`
+	}
+
+	r := Router()
+	r.Use(Middleware{
+		Before: func(h *ifNoneMatch) *Abort {
+			if h.ETag == `"v1"` {
+				return &Abort{Status: http.StatusNotModified}
+			}
+			return nil
+		},
+	})
+	r.GET("/items", func() string {
+		called = true
+		return "items"
+	})
+	r.GET("/old", func() string { return "" }, WithMiddleware(Middleware{
+		Before: func() (Headers, Status, Abort) {
+			return Headers{"Location": {"/items"}}, http.StatusMovedPermanently, Abort{}
+		},
+	}))
+
+	response := makeRequest(t, r, http.MethodGet, "/items", withHeader("If-None-Match", `"v1"`))
+	assert.Equal(t, http.StatusNotModified, response.Code)
+	assert.Empty(t, response.Body.String())
+	assert.False(t, called)
+
+	response = makeRequest(t, r, http.MethodGet, "/items")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, called)
+
+	response = makeRequest(t, r, http.MethodGet, "/old")
+	assert.Equal(t, http.StatusMovedPermanently, response.Code)
+	assert.Equal(t, "/items", response.Header().Get("Location"))
+
+	assert.Panics(t, func() { r.GET("/abort", func() *Abort { return nil }) })
+	assert.Panics(t, func() {
+		r.GET("/after", func() {}, WithMiddleware(Middleware{After: func() *Abort { return nil }}))
+	})
+}
+
+func TestBeforeMiddlewareResponseDocs(t *testing.T) {
+	r := Router()
+	r.Use(Middleware{
+		Before: func() *rateLimited { return &rateLimited{Message: "slow down"} },
+	})
+	r.GET("/items", func() *cachedItem { return nil })
+
+	response := makeRequest(t, r, http.MethodGet, "/items")
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	assert.Equal(t, `{"message":"slow down"}`, response.Body.String())
+
+	doc := generateDocs(t, r)
+	responses := doc.Paths["/items"].Get.Responses
+	assert.Equal(t, "#/components/schemas/rateLimited", responses.Get(429).Value.Content.Get("application/json").Schema.Ref)
+	assert.Equal(t, "#/components/schemas/cachedItem", responses.Get(200).Value.Content.Get("application/json").Schema.Ref)
+}
@@ -0,0 +1,221 @@
+package gnext
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type customId string
+
+type upperName struct {
+	value string
+}
+
+func (n *upperName) UnmarshalText(text []byte) error {
+	if len(text) > 10 {
+		return fmt.Errorf("name too long")
+	}
+	n.value = strings.ToUpper(string(text))
+	return nil
+}
+
+func TestTypedPathParams(t *testing.T) {
+	r := Router()
+	r.GET("/items/:id/:count/:price/:active/:code/:name/", func(id int64, count uint, price float64, active bool, code customId, name upperName) string {
+		return fmt.Sprintf("%d %d %.2f %t %s %s", id, count, price, active, code, name.value)
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/items/-5/3/2.5/true/abc/john/")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"-5 3 2.50 true abc JOHN"`, response.Body.String())
+
+	cases := []string{
+		"/items/x/3/2.5/true/abc/john/",
+		"/items/-5/-3/2.5/true/abc/john/",
+		"/items/-5/3/x/true/abc/john/",
+		"/items/-5/3/2.5/maybe/abc/john/",
+		"/items/-5/3/2.5/true/abc/verylongname/",
+	}
+	for _, path := range cases {
+		response = makeRequest(t, r, http.MethodGet, path)
+		assert.Equalf(t, http.StatusNotFound, response.Code, "path: %s", path)
+	}
+}
+
+func TestOptionalTypedPathParam(t *testing.T) {
+	r := Router()
+	r.GET("/items/:id/", func(id *uint) string {
+		if id == nil {
+			return "none"
+		}
+		return fmt.Sprint(*id)
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/items/7/")
+	assert.Equal(t, `"7"`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/items/abc/")
+	assert.Equal(t, `"none"`, response.Body.String())
+}
+
+func TestPathParamsBeforeMiddlewareValues(t *testing.T) {
+	r := Router()
+	r.Use(Middleware{
+		Before: func() string {
+			return "token"
+		},
+	})
+	r.GET("/items/:id/", func(id string, token string) []string {
+		return []string{id, token}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/items/5/")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `["5","token"]`, response.Body.String())
+}
+
+func TestMiddlewareValuesBeforePathParams(t *testing.T) {
+	type role string
+
+	r := Router()
+	r.Use(Middleware{
+		Before: func() role {
+			return "admin"
+		},
+	})
+	r.GET("/items/:id/", func(role role, id int) []string {
+		return []string{string(role), fmt.Sprint(id)}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/items/5/")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `["admin","5"]`, response.Body.String())
+}
+
+func TestTypedPathParamsDocs(t *testing.T) {
+	r := Router()
+	r.GET("/items/:id/:price/:active/:code/:name/", func(id int64, price float32, active *bool, code customId, name upperName) {})
+
+	doc := generateDocs(t, r)
+
+	params := doc.Paths["/items/{id}/{price}/{active}/{code}/{name}/"].Get.Parameters
+	assert.Len(t, params, 5)
+
+	expected := []struct {
+		name   string
+		type_  string
+		format string
+	}{
+		{"id", "integer", "int64"},
+		{"price", "number", ""},
+		{"active", "boolean", ""},
+		{"code", "string", ""},
+		{"name", "string", ""},
+	}
+	for i, e := range expected {
+		assert.Equal(t, e.name, params[i].Value.Name)
+		assert.Equal(t, "path", params[i].Value.In)
+		assert.True(t, params[i].Value.Required)
+		assert.Equal(t, e.type_, params[i].Value.Schema.Value.Type)
+		assert.Equal(t, e.format, params[i].Value.Schema.Value.Format)
+	}
+}
+
+type shopPath struct {
+	Path
+	ShopId  string `uri:"shopId" binding:"required,uuid"`
+	OrderId int    `uri:"orderId" binding:"min=1"`
+}
+
+func TestPathStructBinding(t *testing.T) {
+	r := Router()
+	r.GET("/shops/:shopId/orders/:orderId", func(path *shopPath) string {
+		return fmt.Sprintf("%s %d", path.ShopId, path.OrderId)
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/shops/5e0c2b6a-4b8f-4d6e-9a53-0f1e2d3c4b5a/orders/12")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"5e0c2b6a-4b8f-4d6e-9a53-0f1e2d3c4b5a 12"`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/shops/not-uuid/orders/12")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = makeRequest(t, r, http.MethodGet, "/shops/5e0c2b6a-4b8f-4d6e-9a53-0f1e2d3c4b5a/orders/abc")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestPathStructCatchAll(t *testing.T) {
+	type filePath struct {
+		Path
+		ShopId   int    `uri:"shopId"`
+		FilePath string `uri:"filepath"`
+	}
+
+	r := Router()
+	r.GET("/shops/:shopId/files/*filepath", func(path *filePath, shopId int, file string) []string {
+		return []string{fmt.Sprint(path.ShopId), path.FilePath, file}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/shops/3/files/a/b.txt")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `["3","a/b.txt","a/b.txt"]`, response.Body.String())
+}
+
+func TestPathStructDocs(t *testing.T) {
+	r := Router()
+	r.GET("/shops/:shopId/orders/:orderId", func(path shopPath) {})
+
+	doc := generateDocs(t, r)
+
+	params := doc.Paths["/shops/{shopId}/orders/{orderId}"].Get.Parameters
+	assert.Len(t, params, 2)
+
+	shopId := params.GetByInAndName("path", "shopId")
+	assert.True(t, shopId.Required)
+	assert.Equal(t, "string", shopId.Schema.Value.Type)
+	assert.Equal(t, "uuid", shopId.Schema.Value.Format)
+
+	orderId := params.GetByInAndName("path", "orderId")
+	assert.True(t, orderId.Required)
+	assert.Equal(t, "integer", orderId.Schema.Value.Type)
+	assert.Equal(t, float64(1), *orderId.Schema.Value.Min)
+}
+
+func TestTrailingAndCatchAllPathParams(t *testing.T) {
+	r := Router()
+	r.GET("/shops/:shop_id", func(shopId int) int {
+		return shopId
+	})
+	r.GET("/shops/:shop_id/files/*file_path", func(shopId int, filePath *string) string {
+		if filePath == nil {
+			return fmt.Sprintf("%d: root", shopId)
+		}
+		return fmt.Sprintf("%d: %s", shopId, *filePath)
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/shops/3")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `3`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/shops/3/files/images/logo.png")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"3: images/logo.png"`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/shops/3/files/")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"3: root"`, response.Body.String())
+
+	doc := generateDocs(t, r)
+
+	operation := doc.Paths["/shops/{shop_id}"].Get
+	assert.Equal(t, []string{"shops"}, operation.Tags)
+	assert.NotNil(t, operation.Parameters.GetByInAndName("path", "shop_id"))
+
+	operation = doc.Paths["/shops/{shop_id}/files/{file_path}"].Get
+	assert.Equal(t, []string{"shops", "files"}, operation.Tags)
+	assert.NotNil(t, operation.Parameters.GetByInAndName("path", "shop_id"))
+	assert.NotNil(t, operation.Parameters.GetByInAndName("path", "file_path"))
+}
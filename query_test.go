@@ -0,0 +1,81 @@
+package gnext
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type priceRange struct {
+	Min int `form:"min"`
+	Max int `form:"max"`
+}
+
+type orderFilter struct {
+	Status string      `form:"status" binding:"omitempty,oneof=new paid"`
+	Price  *priceRange `form:"price"`
+}
+
+type ordersQuery struct {
+	Query
+	Search string              `form:"search"`
+	Filter orderFilter         `form:"filter"`
+	Labels map[string]string   `form:"labels"`
+	Counts map[string][]int    `form:"counts"`
+	Sort   map[string]string   `form:"sort"`
+	Extra  *map[string]float64 `form:"extra"`
+}
+
+func TestDeepObjectQuery(t *testing.T) {
+	var got *ordersQuery
+	r := Router()
+	r.GET("/orders", func(q *ordersQuery) string {
+		got = q
+		return "ok"
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/orders?search=book&filter[status]=paid&filter[price][min]=10&filter[price][max]=20"+
+		"&labels[color]=red&labels[size]=&counts[a]=1&counts[a]=2&extra[x]=1.5")
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	assert.Equal(t, "book", got.Search)
+	assert.Equal(t, "paid", got.Filter.Status)
+	assert.Equal(t, &priceRange{Min: 10, Max: 20}, got.Filter.Price)
+	assert.Equal(t, map[string]string{"color": "red", "size": ""}, got.Labels)
+	assert.Equal(t, map[string][]int{"a": {1, 2}}, got.Counts)
+	assert.Nil(t, got.Sort)
+	assert.Equal(t, map[string]float64{"x": 1.5}, *got.Extra)
+
+	response = makeRequest(t, r, http.MethodGet, "/orders?filter[status]=lost")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = makeRequest(t, r, http.MethodGet, "/orders?counts[a]=many")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "invalid query parameter 'counts'")
+
+	response = makeRequest(t, r, http.MethodGet, "/orders?status=paid&min=5&filter[price][max]=20")
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "", got.Filter.Status)
+	assert.Equal(t, &priceRange{Max: 20}, got.Filter.Price)
+}
+
+func TestDeepObjectQueryDocs(t *testing.T) {
+	r := Router()
+	r.GET("/orders", func(q *ordersQuery) string { return "" })
+
+	doc := generateDocs(t, r)
+	parameters := doc.Paths["/orders"].Get.Parameters
+
+	filter := parameters.GetByInAndName("query", "filter")
+	assert.Equal(t, "deepObject", filter.Style)
+	assert.True(t, *filter.Explode)
+	assert.Equal(t, []interface{}{"new", "paid"}, filter.Schema.Value.Properties["status"].Value.Enum)
+	assert.Equal(t, "integer", filter.Schema.Value.Properties["price"].Value.Properties["min"].Value.Type)
+
+	labels := parameters.GetByInAndName("query", "labels")
+	assert.Equal(t, "deepObject", labels.Style)
+	assert.Equal(t, "string", labels.Schema.Value.AdditionalProperties.Schema.Value.Type)
+
+	assert.Empty(t, parameters.GetByInAndName("query", "search").Style)
+}
@@ -12,3 +12,45 @@ func typesEqual(expected reflect.Type, given reflect.Type) bool {
 func isPtr(arg reflect.Type) bool {
 	return arg.Kind() == reflect.Ptr
 }
+
+func directType(t reflect.Type) reflect.Type {
+	for isPtr(t) {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isBuiltinType reports whether the type, or the type it points to, is predeclared, like string or int.
+func isBuiltinType(t reflect.Type) bool {
+	t = directType(t)
+	return t.Name() != "" && t.PkgPath() == ""
+}
+
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isRequestMarker reports whether the type is explicitly marked as a part of the request, e.g. by embedding Body.
+func isRequestMarker(arg reflect.Type) bool {
+	for _, marker := range []reflect.Type{bodyInterfaceType, queryInterfaceType, headersInterfaceType, cookiesInterfaceType, pathInterfaceType} {
+		if arg.Implements(marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNilable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return true
+	}
+	return false
+}
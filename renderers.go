@@ -0,0 +1,131 @@
+package gnext
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+)
+
+// Renderer writes responses in a custom format, e.g. CSV or NDJSON.
+type Renderer interface {
+	// ContentType is the media type produced by the renderer. It is matched with the `Accept` header.
+	ContentType() string
+	// Render writes the value with the given status to the response.
+	Render(ctx *gin.Context, status int, value interface{}) error
+}
+
+// typedRenderer is a renderer limited to the given response types. If there are no types, it can render any value.
+type typedRenderer struct {
+	Renderer
+	types []reflect.Type
+}
+
+func newTypedRenderer(renderer Renderer, responseTypes []interface{}) *typedRenderer {
+	if renderer == nil {
+		panic("renderer can not be nil")
+	}
+	typed := &typedRenderer{Renderer: renderer}
+	for _, responseType := range responseTypes {
+		typed.types = append(typed.types, directType(reflect.TypeOf(responseType)))
+	}
+	return typed
+}
+
+func (r *typedRenderer) typed() bool {
+	return len(r.types) > 0
+}
+
+func (r *typedRenderer) accepts(t reflect.Type) bool {
+	if !r.typed() {
+		return true
+	}
+	t = directType(t)
+	for _, type_ := range r.types {
+		if type_ == t {
+			return true
+		}
+	}
+	return false
+}
+
+type renderers []*typedRenderer
+
+func (r renderers) copy() renderers {
+	return append(renderers{}, r...)
+}
+
+// find returns the most recently registered renderer for the content type accepting the given type.
+func (r renderers) find(contentType string, t reflect.Type) *typedRenderer {
+	for i := len(r) - 1; i >= 0; i-- {
+		if r[i].ContentType() == contentType && r[i].accepts(t) {
+			return r[i]
+		}
+	}
+	return nil
+}
+
+// contentTypes returns all the content types the value of given type can be rendered in, ordered by priority:
+// renderers registered for this type, then media types and at the end renderers accepting any type.
+func (w *HandlerWrapper) contentTypes(t reflect.Type) []string {
+	if isBinaryType(t) {
+		return []string{mimeOctetStream}
+	}
+
+	var typed, untyped []string
+	for i := len(w.renderers) - 1; i >= 0; i-- {
+		renderer := w.renderers[i]
+		if !renderer.accepts(t) {
+			continue
+		}
+		if renderer.typed() {
+			typed = appendUnique(typed, renderer.ContentType())
+		} else {
+			untyped = appendUnique(untyped, renderer.ContentType())
+		}
+	}
+
+	result := typed
+	for _, contentType := range append(w.mediaTypes.forType(t), untyped...) {
+		result = appendUnique(result, contentType)
+	}
+	return result
+}
+
+// render writes the response in the format negotiated with the `Accept` header.
+// If no offered format is acceptable, the first one is used.
+func (w *HandlerWrapper) render(ctx *gin.Context, status int, value reflect.Value) {
+	if isBinaryType(value.Type()) {
+		renderBinary(ctx, status, value)
+		return
+	}
+
+	offered := w.contentTypes(value.Type())
+	contentType := ctx.NegotiateFormat(offered...)
+	if contentType == "" {
+		contentType = offered[0]
+	}
+
+	renderer := w.renderers.find(contentType, value.Type())
+	if renderer == nil {
+		knownMediaTypes[contentType].render(ctx, status, value.Interface())
+		return
+	}
+
+	if err := renderer.Render(ctx, status, value.Interface()); err != nil {
+		if ctx.Writer.Written() {
+			_ = ctx.Error(err)
+			return
+		}
+		_ = ctx.AbortWithError(http.StatusInternalServerError, fmt.Errorf("rendering '%s' failed: %w", contentType, err))
+	}
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, item := range list {
+		if item == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,138 @@
+package gnext
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type report struct {
+	Rows [][]string `json:"rows"`
+}
+
+type csvRenderer struct{}
+
+func (csvRenderer) ContentType() string {
+	return "text/csv"
+}
+
+func (csvRenderer) Render(ctx *gin.Context, status int, value interface{}) error {
+	r, ok := value.(*report)
+	if !ok {
+		return fmt.Errorf("unexpected value: %T", value)
+	}
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Status(status)
+	return csv.NewWriter(ctx.Writer).WriteAll(r.Rows)
+}
+
+type ndjsonRenderer struct{}
+
+func (ndjsonRenderer) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (ndjsonRenderer) Render(ctx *gin.Context, status int, value interface{}) error {
+	ctx.Header("Content-Type", "application/x-ndjson")
+	ctx.Status(status)
+	encoder := json.NewEncoder(ctx.Writer)
+	if items, ok := value.([]string); ok {
+		for _, item := range items {
+			if err := encoder.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return encoder.Encode(value)
+}
+
+func TestRendererSelectedByType(t *testing.T) {
+	r := Router()
+	r.Renderer(csvRenderer{}, report{})
+	r.GET("/report", func() *report {
+		return &report{Rows: [][]string{{"a", "b"}, {"1", "2"}}}
+	})
+	r.GET("/other", func() []string {
+		return []string{"a"}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/report")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "text/csv", response.Header().Get("Content-Type"))
+	assert.Equal(t, "a,b\n1,2\n", response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/report", withHeader("Accept", "application/json"))
+	assert.JSONEq(t, `{"rows": [["a", "b"], ["1", "2"]]}`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/other", withHeader("Accept", "text/csv"))
+	assert.Equal(t, `["a"]`, response.Body.String())
+}
+
+func TestRendererSelectedByAccept(t *testing.T) {
+	r := Router()
+	r.Renderer(ndjsonRenderer{})
+	r.GET("/items", func() []string {
+		return []string{"a", "b"}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/items")
+	assert.Equal(t, `["a","b"]`, response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/items", withHeader("Accept", "application/x-ndjson"))
+	assert.Equal(t, "application/x-ndjson", response.Header().Get("Content-Type"))
+	assert.Equal(t, "\"a\"\n\"b\"\n", response.Body.String())
+}
+
+func TestRendererPerRoute(t *testing.T) {
+	r := Router()
+	r.GET("/items", func() []string {
+		return []string{"a"}
+	}, WithRenderer(ndjsonRenderer{}, []string{}))
+	r.GET("/other", func() []string {
+		return []string{"a"}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/items")
+	assert.Equal(t, "\"a\"\n", response.Body.String())
+
+	response = makeRequest(t, r, http.MethodGet, "/other", withHeader("Accept", "application/x-ndjson"))
+	assert.Equal(t, `["a"]`, response.Body.String())
+}
+
+func TestRendererError(t *testing.T) {
+	r := Router()
+	r.Renderer(csvRenderer{})
+	r.GET("/items", func() []string {
+		return []string{"a"}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/items", withHeader("Accept", "text/csv"))
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+}
+
+func TestRenderersDocs(t *testing.T) {
+	r := Router()
+	r.Renderer(ndjsonRenderer{})
+	r.GET("/report", func() *report {
+		return nil
+	}, WithRenderer(csvRenderer{}, report{}))
+
+	doc := generateDocs(t, r)
+
+	content := doc.Paths["/report"].Get.Responses.Get(200).Value.Content
+	require.Len(t, content, 3)
+	for _, contentType := range []string{"text/csv", "application/json", "application/x-ndjson"} {
+		require.NotNil(t, content.Get(contentType), contentType)
+		assert.Equal(t, "object", content.Get(contentType).Schema.Value.Type)
+	}
+
+	errorContent := doc.Paths["/report"].Get.Responses.Get(500).Value.Content
+	assert.Len(t, errorContent, 2)
+	assert.Nil(t, errorContent.Get("text/csv"))
+}
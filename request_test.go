@@ -8,22 +8,62 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
 type option func(recorder *httptest.ResponseRecorder) *httptest.ResponseRecorder
 
-func makeRequest(t *testing.T, router *RootRouter, method, url string, body ...interface{}) *httptest.ResponseRecorder {
+// requestOption modifies the request made by makeRequest, e.g. sets a header.
+type requestOption func(req *http.Request)
+
+// withHeader sets the request header, unless the value is empty.
+func withHeader(key, value string) requestOption {
+	return func(req *http.Request) {
+		if value != "" {
+			req.Header.Set(key, value)
+		}
+	}
+}
+
+// withRawBody sends the body as it is, instead of encoding it as JSON.
+func withRawBody(body string) requestOption {
+	return func(req *http.Request) {
+		req.Body = io.NopCloser(strings.NewReader(body))
+		req.ContentLength = int64(len(body))
+	}
+}
+
+// withCookies adds the cookies to the request.
+func withCookies(cookies ...*http.Cookie) requestOption {
+	return func(req *http.Request) {
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+	}
+}
+
+// makeRequest sends the request to the router. The optional arguments are request options,
+// or the body, which is encoded as JSON.
+func makeRequest(t *testing.T, router *RootRouter, method, url string, args ...interface{}) *httptest.ResponseRecorder {
 	var payload io.Reader = nil
+	var options []requestOption
 
-	if len(body) > 0 {
-		payloadBytes, err := json.Marshal(body[0])
+	for _, arg := range args {
+		if option, isOption := arg.(requestOption); isOption {
+			options = append(options, option)
+			continue
+		}
+		payloadBytes, err := json.Marshal(arg)
 		require.NoError(t, err)
 		payload = bytes.NewReader(payloadBytes)
 	}
 
 	req, err := http.NewRequestWithContext(context.Background(), method, url, payload)
 	require.NoError(t, err)
+	for _, option := range options {
+		option(req)
+	}
 
 	response := httptest.NewRecorder()
 	router.ServeHTTP(response, req)
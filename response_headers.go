@@ -0,0 +1,95 @@
+package gnext
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// ResponseHeadersInterface is a struct returned from handlers, whose fields are sent as response headers.
+type ResponseHeadersInterface interface {
+	GnResponseHeaders()
+}
+
+// ResponseHeaders marks a struct returned from handlers as typed response headers.
+// Each field with the `header` tag is sent as the header of this name, e.g.:
+//
+//	type RateLimitHeaders struct {
+//		gnext.ResponseHeaders
+//		Remaining int `header:"X-RateLimit-Remaining"`
+//	}
+//
+// Nil pointers and slices are not sent, slices are sent as repeated headers and time.Time in the HTTP date format.
+// In the documentation, the headers are added to the success response of the endpoint,
+// or the responses listed in the `status_codes` tag of the marker, e.g. `status_codes:"200,304"`.
+type ResponseHeaders struct{}
+
+func (m ResponseHeaders) GnResponseHeaders() {}
+
+var responseHeadersInterfaceType = reflect.TypeOf((*ResponseHeadersInterface)(nil)).Elem()
+
+func responseHeadersSetter(value *reflect.Value, ctx *callContext) {
+	headers := directValue(*value)
+	if !headers.IsValid() {
+		return
+	}
+
+	headersType := headers.Type()
+	for i := 0; i < headersType.NumField(); i++ {
+		name := headersType.Field(i).Tag.Get("header")
+		if name == "" {
+			continue
+		}
+
+		field := headers.Field(i)
+		values := []reflect.Value{field}
+		if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+			values = values[:0]
+			for j := 0; j < field.Len(); j++ {
+				values = append(values, field.Index(j))
+			}
+		}
+		ctx.rawContext.Writer.Header().Del(name)
+
+		for _, headerValue := range values {
+			headerValue = directValue(headerValue)
+			if !headerValue.IsValid() {
+				continue
+			}
+			header, err := formatHeader(headerValue)
+			if err != nil {
+				err = fmt.Errorf("cannot format '%s' header: %w", name, err)
+				errValue := reflect.ValueOf(&err).Elem()
+				ctx.error = &errValue
+				return
+			}
+			ctx.rawContext.Writer.Header().Add(name, header)
+		}
+	}
+}
+
+func formatHeader(value reflect.Value) (string, error) {
+	switch v := value.Interface().(type) {
+	case time.Time:
+		return v.UTC().Format(http.TimeFormat), nil
+	case []byte:
+		return string(v), nil
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		return string(text), err
+	}
+	return fmt.Sprint(value.Interface()), nil
+}
+
+// directValue dereferences the pointers, returning an invalid value if any of them is nil.
+func directValue(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
@@ -0,0 +1,119 @@
+package gnext
+
+import (
+	"fmt"
+	"github.com/meteran/gnext/docs"
+)
+
+// RouteOption configures a single route. It is passed to the route registration methods like GET or POST and can be:
+//   - *docs.Endpoint - documentation of the endpoint, the same as WithDocs,
+//   - an option returned by one of the `With...` functions, e.g. WithDocs, WithRenderer or WithMiddleware.
+type RouteOption interface {
+	GnRouteOption()
+}
+
+type routeOptions struct {
+	doc                *docs.Endpoint
+	renderers          renderers
+	middlewares        middlewares
+	skippedMiddlewares []string
+}
+
+type rendererOption struct {
+	renderer *typedRenderer
+}
+
+func (rendererOption) GnRouteOption() {}
+
+type middlewareOption struct {
+	middlewares middlewares
+}
+
+func (middlewareOption) GnRouteOption() {}
+
+type skipMiddlewareOption struct {
+	names []string
+}
+
+func (skipMiddlewareOption) GnRouteOption() {}
+
+// WithDocs sets the documentation of a single route, e.g. `r.GET("/shops", handler, WithDocs(&docs.Endpoint{Summary: "shops"}))`.
+// It is the same as passing *docs.Endpoint directly.
+func WithDocs(doc *docs.Endpoint) RouteOption {
+	return doc
+}
+
+// WithRenderer registers the renderer for a single route. See routerGroup.Renderer for details.
+func WithRenderer(renderer Renderer, responseTypes ...interface{}) RouteOption {
+	return rendererOption{renderer: newTypedRenderer(renderer, responseTypes)}
+}
+
+// WithMiddleware adds the middlewares to a single route. They are called after the middlewares of the group,
+// in the given order, e.g. `r.GET("/me", handler, WithMiddleware(auth))`.
+func WithMiddleware(middlewares ...Middleware) RouteOption {
+	option := middlewareOption{}
+	for i := range middlewares {
+		option.middlewares = append(option.middlewares, &middlewares[i])
+	}
+	return option
+}
+
+// WithoutMiddleware skips the middlewares of the group with given names (see Middleware.Name) for a single route,
+// e.g. to make one endpoint of the authenticated group public.
+// It panics at registration if the group has no middleware with one of the names.
+func WithoutMiddleware(names ...string) RouteOption {
+	for _, name := range names {
+		if name == "" {
+			panic("cannot skip middleware without a name")
+		}
+	}
+	return skipMiddlewareOption{names: names}
+}
+
+func newRouteOptions(options []RouteOption) *routeOptions {
+	result := &routeOptions{}
+	for _, option := range options {
+		switch o := option.(type) {
+		case nil:
+		case *docs.Endpoint:
+			if result.doc != nil {
+				panic("ambiguous route documentation: only one *docs.Endpoint is allowed")
+			}
+			result.doc = o
+		case rendererOption:
+			result.renderers = append(result.renderers, o.renderer)
+		case middlewareOption:
+			result.middlewares = append(result.middlewares, o.middlewares...)
+		case skipMiddlewareOption:
+			result.skippedMiddlewares = append(result.skippedMiddlewares, o.names...)
+		default:
+			panic(fmt.Sprintf("unknown route option: %T", option))
+		}
+	}
+	return result
+}
+
+// routeMiddlewares returns the middlewares of the group, without the skipped ones, followed by the middlewares of the route.
+func (o *routeOptions) routeMiddlewares(groupMiddlewares middlewares) middlewares {
+	result := middlewares{}
+	for _, name := range o.skippedMiddlewares {
+		if !groupMiddlewares.contains(name) {
+			panic(fmt.Sprintf("cannot skip middleware '%s': there is no such middleware in the group", name))
+		}
+	}
+	for _, middleware := range groupMiddlewares {
+		if !o.skips(middleware) {
+			result = append(result, middleware)
+		}
+	}
+	return append(result, o.middlewares...)
+}
+
+func (o *routeOptions) skips(middleware *Middleware) bool {
+	for _, name := range o.skippedMiddlewares {
+		if middleware.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -1,34 +1,77 @@
 package gnext
 
 import (
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
-	"github.com/gin-gonic/gin/binding"
-	"github.com/go-playground/validator/v10"
 	"github.com/meteran/gnext/docs"
-	"log"
 	"net/http"
-	"reflect"
 	"strings"
+	"sync"
 )
 
 // Router is a RootRouter constructor. It gets one optional parameter *docs.Options.
 // If passed, all non-empty fields from this struct will be used to initialize the documentation.
 func Router(docsOptions ...*docs.Options) *RootRouter {
-	r := gin.Default()
-
 	docsOptions = append(docsOptions, &docs.Options{})
+	return RouterWithOptions(&RouterOptions{Docs: docsOptions[0]})
+}
+
+// RouterOptions configures the RootRouter created by RouterWithOptions.
+type RouterOptions struct {
+	// Docs are the documentation options, the same as passed to Router.
+	Docs *docs.Options
+
+	// Engine is the Gin engine used by the router. If nil, the new one is created.
+	Engine *gin.Engine
+
+	// DisableDefaultMiddlewares prevents adding Gin's logger and recovery middlewares to the created engine.
+	// It has no effect if the Engine is given.
+	DisableDefaultMiddlewares bool
+
+	// TrustedProxies are networks or IP addresses of proxies trusted to set client IP headers.
+	// If nil, the engine setting is not changed. An empty slice means that no proxy is trusted.
+	TrustedProxies []string
 
-	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		v.RegisterTagNameFunc(func(fld reflect.StructField) string {
-			name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+	// DisableJSONTagNames makes validation errors report Go field names instead of names from `json` tags.
+	DisableJSONTagNames bool
 
-			if name == "-" {
-				return ""
-			}
+	// ErrorHandler replaces the DefaultErrorHandler for errors without more specific handlers.
+	// It has to follow the same rules as handlers registered with OnError and accept exactly `error` type.
+	ErrorHandler interface{}
+}
+
+func (o *RouterOptions) engine() *gin.Engine {
+	if o.Engine != nil {
+		return o.Engine
+	}
+	if o.DisableDefaultMiddlewares {
+		return gin.New()
+	}
+	return gin.Default()
+}
 
-			return name
-		})
+// RouterWithOptions creates a RootRouter configured with the given options.
+// If options are nil, it works the same as Router.
+func RouterWithOptions(options *RouterOptions) *RootRouter {
+	if options == nil {
+		options = &RouterOptions{}
+	}
+	docsOptions := options.Docs
+	if docsOptions == nil {
+		docsOptions = &docs.Options{}
+	}
+
+	r := options.engine()
+	if options.TrustedProxies != nil {
+		if err := r.SetTrustedProxies(options.TrustedProxies); err != nil {
+			panic(fmt.Sprintf("invalid trusted proxies: %s", err))
+		}
+	}
+
+	errorHandlers := newErrorHandlers()
+	if options.ErrorHandler != nil {
+		errorHandlers.setupDefault(options.ErrorHandler)
 	}
 
 	return &RootRouter{
@@ -36,8 +79,10 @@ func Router(docsOptions ...*docs.Options) *RootRouter {
 			pathPrefix:    "",
 			rawRouter:     r,
 			middlewares:   middlewares{},
-			Docs:          docs.New(docsOptions[0]),
-			errorHandlers: newErrorHandlers(),
+			Docs:          docs.New(docsOptions),
+			errorHandlers: errorHandlers,
+			mediaTypes:    defaultMediaTypes,
+			validate:      newValidator(!options.DisableJSONTagNames),
 		},
 		engine: r,
 	}
@@ -47,7 +92,10 @@ func Router(docsOptions ...*docs.Options) *RootRouter {
 // All other operations are made using this router.
 type RootRouter struct {
 	routerGroup
-	engine *gin.Engine
+	engine         *gin.Engine
+	startupHooks   []LifecycleHook
+	shutdownHooks  []LifecycleHook
+	docsRoutesOnce sync.Once
 }
 
 // Engine returns the raw Gin engine.
@@ -63,21 +111,10 @@ func (r *RootRouter) Engine() *gin.Engine {
 //   - 1 - means the given address is either a full address in form 'host:port` or, if doesn't contain ':',  a port.
 //   - 2 - first parameter is a host, the latter one is a port.
 //   - 3+ - invalid address.
+//
+// The server runs until it fails. See RunContext and RunWithSignals for graceful shutdown.
 func (r *RootRouter) Run(address ...string) error {
-	host, port := resolveAddress(address)
-	r.Docs.RegisterRoutes(r.rawRouter)
-
-	srv := &http.Server{
-		Addr:    fmt.Sprintf("%s:%s", host, port),
-		Handler: r.engine,
-	}
-
-	if host == "" {
-		host = "localhost"
-	}
-
-	log.Printf("starting server on http://%s:%s", host, port)
-	return srv.ListenAndServe()
+	return r.RunContext(context.Background(), nil, address...)
 }
 
 // ServeHTTP executes the request from `req`, runs handlers and writes response to the `response` parameter.
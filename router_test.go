@@ -0,0 +1,76 @@
+package gnext
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouterWithCustomEngine(t *testing.T) {
+	engine := gin.New()
+	r := RouterWithOptions(&RouterOptions{Engine: engine})
+	r.GET("/hello", func() string {
+		return "hello"
+	})
+
+	assert.Same(t, engine, r.Engine())
+
+	response := httptest.NewRecorder()
+	engine.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/hello", nil))
+	assert.Equal(t, `"hello"`, response.Body.String())
+}
+
+func TestRouterDefaultMiddlewares(t *testing.T) {
+	assert.Len(t, Router().Engine().Handlers, 2)
+	assert.Empty(t, RouterWithOptions(&RouterOptions{DisableDefaultMiddlewares: true}).Engine().Handlers)
+}
+
+func TestRouterTrustedProxies(t *testing.T) {
+	r := RouterWithOptions(&RouterOptions{TrustedProxies: []string{"10.0.0.0/8"}})
+	r.GET("/ip", func(ctx *gin.Context) string {
+		return ctx.ClientIP()
+	})
+
+	request := func(remoteAddr string) string {
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "1.2.3.4")
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, req)
+		return response.Body.String()
+	}
+
+	assert.Equal(t, `"1.2.3.4"`, request("10.0.0.1:1234"))
+	assert.Equal(t, `"192.168.0.1"`, request("192.168.0.1:1234"))
+
+	assert.Panics(t, func() {
+		RouterWithOptions(&RouterOptions{TrustedProxies: []string{"invalid"}})
+	})
+}
+
+func TestRouterDefaultErrorHandler(t *testing.T) {
+	r := RouterWithOptions(&RouterOptions{
+		ErrorHandler: func(err error) (string, Status) {
+			return err.Error(), http.StatusTeapot
+		},
+	})
+	r.GET("/error", func() error {
+		return fmt.Errorf("custom error")
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/error")
+	assert.Equal(t, http.StatusTeapot, response.Code)
+	assert.Equal(t, `"custom error"`, response.Body.String())
+
+	assert.Panics(t, func() {
+		RouterWithOptions(&RouterOptions{
+			ErrorHandler: func(err *NotFound) (string, Status) { return "", http.StatusNotFound },
+		})
+	})
+	assert.Panics(t, func() {
+		RouterWithOptions(&RouterOptions{ErrorHandler: "not a function"})
+	})
+}
@@ -0,0 +1,236 @@
+package gnext
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const defaultShutdownTimeout = 10 * time.Second
+
+// ServerOptions configures the http server started by RootRouter.RunContext, RunListener or RunUnix.
+// Zero values mean no limits, as in http.Server.
+type ServerOptions struct {
+	// ReadTimeout is the maximum duration for reading the entire request, including the body.
+	ReadTimeout time.Duration
+
+	// ReadHeaderTimeout is the amount of time allowed to read request headers.
+	ReadHeaderTimeout time.Duration
+
+	// WriteTimeout is the maximum duration before timing out writes of the response.
+	WriteTimeout time.Duration
+
+	// IdleTimeout is the maximum amount of time to wait for the next request when keep-alives are enabled.
+	IdleTimeout time.Duration
+
+	// MaxHeaderBytes controls the maximum number of bytes the server will read parsing the request headers.
+	MaxHeaderBytes int
+
+	// ShutdownTimeout is the maximum time to wait for active requests to finish when the server is stopping.
+	// If not set, the default value is 10 seconds.
+	ShutdownTimeout time.Duration
+
+	// TLSConfig enables serving HTTPS with the given configuration.
+	// The certificates may be provided in the config or in CertFile and KeyFile.
+	TLSConfig *tls.Config
+
+	// CertFile and KeyFile are paths to the certificate and matching private key used to serve HTTPS.
+	CertFile string
+	KeyFile  string
+
+	// H2C enables HTTP/2 over cleartext TCP, i.e. without TLS.
+	H2C bool
+}
+
+func (o *ServerOptions) server(handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		TLSConfig:         o.TLSConfig,
+		ReadTimeout:       o.ReadTimeout,
+		ReadHeaderTimeout: o.ReadHeaderTimeout,
+		WriteTimeout:      o.WriteTimeout,
+		IdleTimeout:       o.IdleTimeout,
+		MaxHeaderBytes:    o.MaxHeaderBytes,
+	}
+}
+
+func (o *ServerOptions) tls() bool {
+	return o.TLSConfig != nil || o.CertFile != ""
+}
+
+func (o *ServerOptions) scheme() string {
+	if o.tls() {
+		return "https"
+	}
+	return "http"
+}
+
+func (o *ServerOptions) serve(srv *http.Server, listener net.Listener) error {
+	if o.tls() {
+		return srv.ServeTLS(listener, o.CertFile, o.KeyFile)
+	}
+	return srv.Serve(listener)
+}
+
+func (o *ServerOptions) shutdownTimeout() time.Duration {
+	if o.ShutdownTimeout == 0 {
+		return defaultShutdownTimeout
+	}
+	return o.ShutdownTimeout
+}
+
+// LifecycleHook is a function called when the server starts or stops.
+type LifecycleHook func(ctx context.Context) error
+
+// OnStartup registers a hook called before the server starts accepting requests.
+// If a hook returns an error, the server is not started and the error is returned from RunContext.
+func (r *RootRouter) OnStartup(hook LifecycleHook) {
+	r.startupHooks = append(r.startupHooks, hook)
+}
+
+// OnShutdown registers a hook called after the server has stopped and all active requests are finished
+// (or the shutdown timeout has passed and the connections are closed). Hooks are called in the reverse order
+// of registration.
+func (r *RootRouter) OnShutdown(hook LifecycleHook) {
+	r.shutdownHooks = append(r.shutdownHooks, hook)
+}
+
+// RunContext starts the http server, just like Run, but it stops gracefully when the context is cancelled.
+// Active requests are drained before returning. The contexts of the requests are cancelled when the server is
+// stopping, so long-running handlers, like streams, should finish then. If options are nil, the default ones are used.
+// Process signals are not handled, see RunWithSignals.
+func (r *RootRouter) RunContext(ctx context.Context, options *ServerOptions, address ...string) error {
+	if options == nil {
+		options = &ServerOptions{}
+	}
+	host, port := resolveAddress(address)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%s", host, port))
+	if err != nil {
+		return err
+	}
+
+	if host == "" {
+		host = "localhost"
+	}
+
+	log.Printf("starting server on %s://%s:%s", options.scheme(), host, port)
+	return r.serve(ctx, options, listener)
+}
+
+// RunWithSignals starts the http server like RunContext, and stops it gracefully when the process receives
+// SIGINT or SIGTERM. The signals are captured only while the server is running.
+func (r *RootRouter) RunWithSignals(options *ServerOptions, address ...string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return r.RunContext(ctx, options, address...)
+}
+
+// RunTLS starts the https server using the certificate and the private key from the given files.
+// The address parameters are the same as in Run.
+func (r *RootRouter) RunTLS(certFile string, keyFile string, address ...string) error {
+	return r.RunContext(context.Background(), &ServerOptions{CertFile: certFile, KeyFile: keyFile}, address...)
+}
+
+// RunListener starts the server accepting connections from the given listener.
+// It stops the same way as RunContext, closing the listener.
+func (r *RootRouter) RunListener(ctx context.Context, options *ServerOptions, listener net.Listener) error {
+	if options == nil {
+		options = &ServerOptions{}
+	}
+	log.Printf("starting server on %s://%s", options.scheme(), listener.Addr())
+	return r.serve(ctx, options, listener)
+}
+
+// RunUnix starts the server on the Unix domain socket under the given path.
+// The stale socket file, left e.g. after a crash, is removed before listening.
+func (r *RootRouter) RunUnix(ctx context.Context, options *ServerOptions, socketPath string) error {
+	if info, err := os.Stat(socketPath); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err = os.Remove(socketPath); err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	return r.RunListener(ctx, options, listener)
+}
+
+// serve runs the server on the listener until the context is done or the server fails.
+func (r *RootRouter) serve(ctx context.Context, options *ServerOptions, listener net.Listener) error {
+	r.registerDocsRoutes()
+
+	handler := http.Handler(r.engine)
+	if options.H2C {
+		r.engine.UseH2C = true
+		handler = r.engine.Handler()
+	}
+	srv := options.server(handler)
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	srv.BaseContext = func(net.Listener) context.Context {
+		return baseCtx
+	}
+
+	for _, hook := range r.startupHooks {
+		if err := hook(ctx); err != nil {
+			_ = listener.Close()
+			return err
+		}
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- options.serve(srv, listener)
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), options.shutdownTimeout())
+		defer cancel()
+
+		// long-running handlers, like streams, never finish on their own, so they are stopped right away
+		cancelRequests()
+		if err = srv.Shutdown(shutdownCtx); err != nil {
+			_ = srv.Close()
+		}
+		if serverErr := <-serveErr; err == nil && serverErr != http.ErrServerClosed {
+			err = serverErr
+		}
+	}
+
+	if hookErr := r.runShutdownHooks(options); err == nil {
+		err = hookErr
+	}
+	return err
+}
+
+func (r *RootRouter) runShutdownHooks(options *ServerOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), options.shutdownTimeout())
+	defer cancel()
+
+	var firstErr error
+	for i := len(r.shutdownHooks) - 1; i >= 0; i-- {
+		if err := r.shutdownHooks[i](ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (r *RootRouter) registerDocsRoutes() {
+	r.docsRoutesOnce.Do(func() {
+		r.Docs.RegisterRoutes(r.rawRouter)
+	})
+}
@@ -0,0 +1,321 @@
+package gnext
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	var calls []string
+
+	requestStarted := make(chan struct{})
+	r := Router()
+	r.GET("/slow", func() string {
+		close(requestStarted)
+		time.Sleep(100 * time.Millisecond)
+		return "done"
+	})
+	r.OnStartup(func(ctx context.Context) error {
+		calls = append(calls, "startup")
+		return nil
+	})
+	r.OnShutdown(func(ctx context.Context) error {
+		calls = append(calls, "first shutdown")
+		return nil
+	})
+	r.OnShutdown(func(ctx context.Context) error {
+		calls = append(calls, "second shutdown")
+		return nil
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- r.RunListener(ctx, &ServerOptions{ShutdownTimeout: time.Second}, listener)
+	}()
+
+	go func() {
+		<-requestStarted
+		cancel()
+	}()
+
+	response, err := http.Get(fmt.Sprintf("http://%s/slow", listener.Addr()))
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, `"done"`, string(body))
+
+	select {
+	case err = <-serveErr:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("server has not stopped")
+	}
+	assert.Equal(t, []string{"startup", "second shutdown", "first shutdown"}, calls)
+}
+
+func TestShutdownStopsStreams(t *testing.T) {
+	var streamStopped, streamStoppedBeforeHook bool
+
+	streamStarted := make(chan struct{})
+	producerDone := make(chan struct{})
+	defer close(producerDone)
+
+	r := Router()
+	r.Use(Middleware{
+		After: func(events <-chan *event) {
+			streamStopped = true
+		},
+	})
+	r.GET("/events", func() <-chan *event {
+		events := make(chan *event)
+		close(streamStarted)
+		go func() {
+			for i := 1; ; i++ {
+				select {
+				case events <- &event{Id: i}:
+				case <-producerDone:
+					return
+				}
+			}
+		}()
+		return events
+	})
+	r.OnShutdown(func(ctx context.Context) error {
+		streamStoppedBeforeHook = streamStopped
+		return nil
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- r.RunListener(ctx, &ServerOptions{ShutdownTimeout: time.Second}, listener)
+	}()
+
+	response, err := http.Get(fmt.Sprintf("http://%s/events", listener.Addr()))
+	require.NoError(t, err)
+	defer response.Body.Close()
+	<-streamStarted
+	cancel()
+
+	select {
+	case err = <-serveErr:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("server has not stopped")
+	}
+	assert.True(t, streamStoppedBeforeHook)
+}
+
+func TestShutdownTimeoutClosesConnections(t *testing.T) {
+	requestStarted := make(chan struct{})
+	r := Router()
+	r.GET("/stuck", func() string {
+		close(requestStarted)
+		time.Sleep(2 * time.Second)
+		return "done"
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- r.RunListener(ctx, &ServerOptions{ShutdownTimeout: 50 * time.Millisecond}, listener)
+	}()
+
+	go func() {
+		<-requestStarted
+		cancel()
+	}()
+
+	requestErr := make(chan error, 1)
+	go func() {
+		response, err := http.Get(fmt.Sprintf("http://%s/stuck", listener.Addr()))
+		if err == nil {
+			response.Body.Close()
+		}
+		requestErr <- err
+	}()
+
+	select {
+	case err = <-serveErr:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("server has not stopped")
+	}
+	select {
+	case err = <-requestErr:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("connection has not been closed")
+	}
+}
+
+func TestStartupHookErrorStopsServer(t *testing.T) {
+	r := Router()
+	r.OnStartup(func(ctx context.Context) error {
+		return fmt.Errorf("startup failed")
+	})
+
+	err := r.RunContext(context.Background(), nil, "127.0.0.1", "0")
+	assert.EqualError(t, err, "startup failed")
+}
+
+func TestRunWithSignals(t *testing.T) {
+	r := Router()
+	r.OnStartup(func(ctx context.Context) error {
+		process, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			return err
+		}
+		return process.Signal(os.Interrupt)
+	})
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- r.RunWithSignals(nil, "127.0.0.1", "0")
+	}()
+
+	select {
+	case err := <-serveErr:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("server has not stopped")
+	}
+}
+
+func TestServerOptions(t *testing.T) {
+	options := &ServerOptions{
+		ReadTimeout:       time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+		WriteTimeout:      3 * time.Second,
+		IdleTimeout:       4 * time.Second,
+		MaxHeaderBytes:    1024,
+	}
+
+	srv := options.server(http.NotFoundHandler())
+	assert.Equal(t, time.Second, srv.ReadTimeout)
+	assert.Equal(t, 2*time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, 3*time.Second, srv.WriteTimeout)
+	assert.Equal(t, 4*time.Second, srv.IdleTimeout)
+	assert.Equal(t, 1024, srv.MaxHeaderBytes)
+	assert.Equal(t, defaultShutdownTimeout, options.shutdownTimeout())
+}
+
+func runInBackground(t *testing.T, run func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-serveErr)
+	})
+}
+
+func TestRunListenerWithTLS(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	tlsConfig := tlsServer.TLS
+	client := tlsServer.Client()
+	tlsServer.Close()
+
+	r := Router()
+	r.GET("/hello", func() string {
+		return "hello"
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	runInBackground(t, func(ctx context.Context) error {
+		return r.RunListener(ctx, &ServerOptions{TLSConfig: tlsConfig}, listener)
+	})
+
+	response, err := client.Get(fmt.Sprintf("https://%s/hello", listener.Addr()))
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `"hello"`, string(body))
+
+	response, err = client.Get(fmt.Sprintf("https://%s/docs.json", listener.Addr()))
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestRunUnix(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "gnext.sock")
+
+	r := Router()
+	r.GET("/hello", func() string {
+		return "hello"
+	})
+
+	runInBackground(t, func(ctx context.Context) error {
+		return r.RunUnix(ctx, nil, socketPath)
+	})
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+		},
+	}}
+
+	var response *http.Response
+	require.Eventually(t, func() bool {
+		var err error
+		response, err = client.Get("http://unix/hello")
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `"hello"`, string(body))
+}
+
+func TestRunListenerWithH2C(t *testing.T) {
+	r := Router()
+	r.GET("/hello", func() string {
+		return "hello"
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	runInBackground(t, func(ctx context.Context) error {
+		return r.RunListener(ctx, &ServerOptions{H2C: true, ShutdownTimeout: 100 * time.Millisecond}, listener)
+	})
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/hello", listener.Addr()), nil)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "Upgrade, HTTP2-Settings")
+	req.Header.Set("Upgrade", "h2c")
+	req.Header.Set("HTTP2-Settings", "AAMAAABkAARAAAAAAAIAAAAA")
+
+	response, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+	assert.Equal(t, "h2c", response.Header.Get("Upgrade"))
+}
@@ -0,0 +1,118 @@
+package gnext
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+const (
+	mimeEventStream = "text/event-stream"
+	mimeNDJSON      = "application/x-ndjson"
+)
+
+// streamContentTypes are the formats of streamed responses, the first one is the default.
+var streamContentTypes = []string{mimeEventStream, mimeNDJSON}
+
+// streamItemType returns the type of values sent by a stream and true, if the given type is a stream.
+// A stream is a receiving channel or an iterator, which is a type with method `Next() (T, bool)`,
+// returning the next value and false when there are no more values.
+func streamItemType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Chan && t.ChanDir()&reflect.RecvDir != 0 {
+		return t.Elem(), true
+	}
+
+	method, exists := t.MethodByName("Next")
+	if !exists {
+		return nil, false
+	}
+	methodType := method.Type
+	receivers := 1
+	if t.Kind() == reflect.Interface {
+		receivers = 0
+	}
+	if methodType.NumIn() != receivers || methodType.NumOut() != 2 || methodType.Out(1).Kind() != reflect.Bool {
+		return nil, false
+	}
+	return methodType.Out(0), true
+}
+
+// releaseStream frees the stream which is not read to the end, e.g. because the handler has failed or the client is gone.
+// A channel is drained in the background, so its producer is not blocked forever, and an iterator implementing io.Closer is closed.
+func releaseStream(stream reflect.Value) {
+	if isNilable(stream.Kind()) && stream.IsNil() {
+		return
+	}
+	if closer, ok := stream.Interface().(io.Closer); ok {
+		_ = closer.Close()
+		return
+	}
+	if stream.Kind() == reflect.Chan {
+		go func() {
+			for {
+				if _, ok := stream.Recv(); !ok {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// stream sends all the values from the stream as Server-Sent Events or newline delimited JSON, depending on `Accept` header.
+// It stops when the stream is finished or the client is gone. The status and headers are written before the first value,
+// so the ones set later, e.g. by after-middlewares, are ignored.
+func (w *HandlerWrapper) stream(ctx *callContext, stream reflect.Value) {
+	rawContext := ctx.rawContext
+	contentType := rawContext.NegotiateFormat(streamContentTypes...)
+	if contentType == "" {
+		contentType = streamContentTypes[0]
+	}
+
+	rawContext.Header("Content-Type", contentType)
+	rawContext.Header("Cache-Control", "no-cache")
+	rawContext.Status(int(ctx.status))
+	rawContext.Writer.WriteHeaderNow()
+	rawContext.Writer.Flush()
+
+	send := func(value reflect.Value) bool {
+		if contentType == mimeEventStream {
+			rawContext.SSEvent("", value.Interface())
+		} else if err := json.NewEncoder(rawContext.Writer).Encode(value.Interface()); err != nil {
+			_ = rawContext.Error(err)
+			return false
+		}
+		rawContext.Writer.Flush()
+		return true
+	}
+
+	if isNilable(stream.Kind()) && stream.IsNil() {
+		return
+	}
+
+	done := reflect.ValueOf(rawContext.Request.Context().Done())
+	if stream.Kind() == reflect.Chan {
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: stream},
+			{Dir: reflect.SelectRecv, Chan: done},
+		}
+		for {
+			chosen, value, ok := reflect.Select(cases)
+			if chosen == 0 && !ok {
+				return
+			}
+			if chosen == 1 || !send(value) {
+				releaseStream(stream)
+				return
+			}
+		}
+	}
+
+	defer releaseStream(stream)
+	next := stream.MethodByName("Next")
+	for rawContext.Request.Context().Err() == nil {
+		results := next.Call(nil)
+		if !results[1].Bool() || !send(results[0]) {
+			return
+		}
+	}
+}
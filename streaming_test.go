@@ -0,0 +1,166 @@
+package gnext
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type event struct {
+	Id int `json:"id"`
+}
+
+type counter struct {
+	current int
+	max     int
+}
+
+func (c *counter) Next() (int, bool) {
+	if c.current >= c.max {
+		return 0, false
+	}
+	c.current++
+	return c.current, true
+}
+
+func TestStreamChannelAsServerSentEvents(t *testing.T) {
+	var afterCalled bool
+
+	r := Router()
+	r.Use(Middleware{
+		After: func(events <-chan *event) {
+			afterCalled = true
+		},
+	})
+	r.GET("/events", func() (<-chan *event, Status) {
+		events := make(chan *event)
+		go func() {
+			defer close(events)
+			for i := 1; i <= 2; i++ {
+				events <- &event{Id: i}
+			}
+		}()
+		return events, http.StatusAccepted
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/events")
+	assert.Equal(t, http.StatusAccepted, response.Code)
+	assert.Equal(t, "text/event-stream", response.Header().Get("Content-Type"))
+	assert.Equal(t, "data:{\"id\":1}\n\ndata:{\"id\":2}\n\n", response.Body.String())
+	assert.True(t, afterCalled)
+}
+
+func TestStreamIteratorAsNDJSON(t *testing.T) {
+	r := Router()
+	r.GET("/numbers", func() *counter {
+		return &counter{max: 3}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/numbers", withHeader("Accept", "application/x-ndjson"))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/x-ndjson", response.Header().Get("Content-Type"))
+	assert.Equal(t, "1\n2\n3\n", response.Body.String())
+}
+
+func TestNilStream(t *testing.T) {
+	r := Router()
+	r.GET("/events", func() chan string {
+		return nil
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/events")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "", response.Body.String())
+}
+
+func TestStreamWithError(t *testing.T) {
+	producerDone := make(chan struct{})
+
+	r := Router()
+	r.GET("/events", func() (<-chan *event, error) {
+		events := make(chan *event)
+		go func() {
+			defer close(producerDone)
+			defer close(events)
+			for i := 1; i <= 2; i++ {
+				events <- &event{Id: i}
+			}
+		}()
+		return events, fmt.Errorf("stream failed")
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/events")
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.NotContains(t, response.Body.String(), "data:")
+
+	select {
+	case <-producerDone:
+	case <-time.After(time.Second):
+		t.Fatal("stream producer is blocked")
+	}
+}
+
+type closingCounter struct {
+	counter
+	closed bool
+}
+
+func (c *closingCounter) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestFailedStreamIsClosed(t *testing.T) {
+	iterator := &closingCounter{counter: counter{max: 3}}
+
+	r := Router()
+	r.GET("/numbers", func() (*closingCounter, error) {
+		return iterator, &NotFound{fmt.Errorf("no numbers")}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/numbers")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.True(t, iterator.closed)
+	assert.Equal(t, 0, iterator.current)
+}
+
+func TestStreamDocs(t *testing.T) {
+	r := Router()
+	r.GET("/events", func() (<-chan *event, error) {
+		return nil, nil
+	})
+
+	doc := generateDocs(t, r)
+
+	responses := doc.Paths["/events"].Get.Responses
+	content := responses.Get(200).Value.Content
+	require.Len(t, content, 2)
+	assert.Equal(t, "integer", content.Get("text/event-stream").Schema.Value.Properties["id"].Value.Type)
+	assert.NotNil(t, content.Get("application/x-ndjson"))
+	assert.NotNil(t, responses.Get(500))
+}
+
+type jobProgress struct {
+	Response `default_status:"202"`
+	counter
+}
+
+func TestStreamStatus(t *testing.T) {
+	r := Router()
+	r.GET("/jobs", func() *jobProgress {
+		return &jobProgress{counter: counter{max: 2}}
+	})
+
+	response := makeRequest(t, r, http.MethodGet, "/jobs", withHeader("Accept", "application/x-ndjson"))
+	assert.Equal(t, http.StatusAccepted, response.Code)
+	assert.Equal(t, "1\n2\n", response.Body.String())
+
+	doc := generateDocs(t, r)
+	responses := doc.Paths["/jobs"].Get.Responses
+	require.NotNil(t, responses.Get(202))
+	assert.NotNil(t, responses.Get(202).Value.Content.Get("application/x-ndjson"))
+	assert.Nil(t, responses.Get(200))
+}
@@ -1,10 +1,13 @@
 package gnext
 
 import (
+	"encoding"
 	"github.com/gin-gonic/gin"
 	"github.com/meteran/gnext/docs"
+	"mime/multipart"
 	"net/http"
 	"reflect"
+	"time"
 )
 
 type IRouter interface {
@@ -16,16 +19,18 @@ type IRouter interface {
 
 type IRoutes interface {
 	Use(Middleware) IRoutes
-
-	Handle(string, string, interface{}, ...*docs.Endpoint) IRoutes
-	Any(string, interface{}, ...*docs.Endpoint) IRoutes
-	GET(string, interface{}, ...*docs.Endpoint) IRoutes
-	POST(string, interface{}, ...*docs.Endpoint) IRoutes
-	DELETE(string, interface{}, ...*docs.Endpoint) IRoutes
-	PATCH(string, interface{}, ...*docs.Endpoint) IRoutes
-	PUT(string, interface{}, ...*docs.Endpoint) IRoutes
-	OPTIONS(string, interface{}, ...*docs.Endpoint) IRoutes
-	HEAD(string, interface{}, ...*docs.Endpoint) IRoutes
+	MediaTypes(...string) IRoutes
+	Renderer(Renderer, ...interface{}) IRoutes
+
+	Handle(string, string, interface{}, ...RouteOption) IRoutes
+	Any(string, interface{}, ...RouteOption) IRoutes
+	GET(string, interface{}, ...RouteOption) IRoutes
+	POST(string, interface{}, ...RouteOption) IRoutes
+	DELETE(string, interface{}, ...RouteOption) IRoutes
+	PATCH(string, interface{}, ...RouteOption) IRoutes
+	PUT(string, interface{}, ...RouteOption) IRoutes
+	OPTIONS(string, interface{}, ...RouteOption) IRoutes
+	HEAD(string, interface{}, ...RouteOption) IRoutes
 }
 
 type Status int
@@ -37,6 +42,13 @@ type Headers http.Header
 
 func (m Headers) GnHeaders() {}
 
+type CookiesInterface interface {
+	GnCookies()
+}
+type Cookies []*http.Cookie
+
+func (m Cookies) GnCookies() {}
+
 type QueryInterface interface {
 	GnQuery()
 }
@@ -44,6 +56,13 @@ type Query struct{}
 
 func (m Query) GnQuery() {}
 
+type PathInterface interface {
+	GnPath()
+}
+type Path struct{}
+
+func (m Path) GnPath() {}
+
 type BodyInterface interface {
 	GnBody()
 }
@@ -51,6 +70,44 @@ type Body struct{}
 
 func (m Body) GnBody() {}
 
+// FormInterface is a request body sent as `application/x-www-form-urlencoded`.
+type FormInterface interface {
+	BodyInterface
+	GnForm()
+}
+type Form struct{ Body }
+
+func (m Form) GnForm() {}
+
+// MultipartFormInterface is a request body sent as `multipart/form-data`.
+// It allows file uploads to the fields of type *multipart.FileHeader or []*multipart.FileHeader.
+type MultipartFormInterface interface {
+	BodyInterface
+	GnMultipartForm()
+}
+type MultipartForm struct{ Body }
+
+func (m MultipartForm) GnMultipartForm() {}
+
+// Abort, returned from a before-middleware, stops the request handling: the next before-middlewares and the handler
+// are skipped, and the after-middlewares matching the middleware are called, as in case of an error.
+// Return nil to continue, e.g. `func(h *CacheHeaders) (*Abort, error)`.
+//
+// The response is sent without the body, e.g. a redirect (with the `Location` header returned as Headers) or 304 Not Modified.
+// To send a response with the body, return a value implementing ResponseInterface from the middleware instead.
+type Abort struct {
+	// Status of the response. If not set, the status returned from the middleware or the default status of the route is used.
+	Status Status
+}
+
+func (a *Abort) apply(ctx *callContext) {
+	ctx.aborted = true
+	if a.Status != 0 {
+		ctx.status = a.Status
+		ctx.statusSet = true
+	}
+}
+
 type ErrorResponse struct{}
 
 type ResponseInterface interface {
@@ -61,22 +118,36 @@ type Response struct{}
 func (m Response) GnResponse() {}
 
 var (
-	queryInterfaceType    = reflect.TypeOf((*QueryInterface)(nil)).Elem()
-	bodyInterfaceType     = reflect.TypeOf((*BodyInterface)(nil)).Elem()
-	errorInterfaceType    = reflect.TypeOf((*error)(nil)).Elem()
-	responseInterfaceType = reflect.TypeOf((*ResponseInterface)(nil)).Elem()
-	headersInterfaceType  = reflect.TypeOf((*HeadersInterface)(nil)).Elem()
+	queryInterfaceType     = reflect.TypeOf((*QueryInterface)(nil)).Elem()
+	bodyInterfaceType      = reflect.TypeOf((*BodyInterface)(nil)).Elem()
+	formInterfaceType      = reflect.TypeOf((*FormInterface)(nil)).Elem()
+	multipartInterfaceType = reflect.TypeOf((*MultipartFormInterface)(nil)).Elem()
+	pathInterfaceType      = reflect.TypeOf((*PathInterface)(nil)).Elem()
+	errorInterfaceType     = reflect.TypeOf((*error)(nil)).Elem()
+	responseInterfaceType  = reflect.TypeOf((*ResponseInterface)(nil)).Elem()
+	headersInterfaceType   = reflect.TypeOf((*HeadersInterface)(nil)).Elem()
+	cookiesInterfaceType   = reflect.TypeOf((*CookiesInterface)(nil)).Elem()
+	textUnmarshalerType    = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
 
 	rawContextType = reflect.TypeOf(&gin.Context{})
 	headersType    = reflect.TypeOf(Headers{})
+	cookiesType    = reflect.TypeOf(Cookies{})
 	statusType     = reflect.TypeOf(Status(0))
-	stringType     = reflect.TypeOf("")
-	intType        = reflect.TypeOf(0)
+	abortType      = reflect.TypeOf(Abort{})
+	timeType       = reflect.TypeOf(time.Time{})
+
+	fileHeaderPtrType = reflect.TypeOf(&multipart.FileHeader{})
+	fileHeadersType   = reflect.TypeOf([]*multipart.FileHeader{})
 )
 
 type Middleware struct {
+	// Name identifies the middleware, so it can be skipped for a single route with WithoutMiddleware.
+	Name   string
 	Before interface{}
 	After  interface{}
+	// Security documents the authentication performed by the middleware, e.g. by BearerAuth.
+	// The routes using the middleware require this security scheme in the documentation.
+	Security *docs.SecurityScheme
 }
 
 type middlewares []*Middleware
@@ -85,6 +156,15 @@ func (m middlewares) copy() middlewares {
 	return append(middlewares{}, m...)
 }
 
+func (m middlewares) contains(name string) bool {
+	for _, middleware := range m {
+		if middleware.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (m middlewares) count() int {
 	count := 0
 	for _, middleware := range m {
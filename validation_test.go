@@ -1,7 +1,10 @@
 package gnext
 
 import (
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
@@ -27,3 +30,102 @@ func TestPayloadValidation(t *testing.T) {
 	res := makeRequest(t, r, "POST", "/handler", gin.H{"name": "some name"})
 	assert.Equal(t, http.StatusBadRequest, res.Code)
 }
+
+type evenRequest struct {
+	Number   int    `json:"number" binding:"even"`
+	Name     string `json:"name" binding:"shortname"`
+	Password string `json:"password"`
+	Repeated string `json:"repeated"`
+}
+
+type evenQuery struct {
+	Query
+	Number int `form:"number" binding:"even"`
+}
+
+func isEven(fl validator.FieldLevel) bool {
+	return fl.Field().Int()%2 == 0
+}
+
+func passwordsMatch(sl validator.StructLevel) {
+	req := sl.Current().Interface().(evenRequest)
+	if req.Password != req.Repeated {
+		sl.ReportError(req.Repeated, "repeated", "Repeated", "eqfield", "password")
+	}
+}
+
+func TestCustomValidations(t *testing.T) {
+	r := Router()
+	r.RegisterValidation("even", isEven, func(schema *openapi3.Schema, _ string) {
+		multipleOf := 2.0
+		schema.MultipleOf = &multipleOf
+	})
+	r.RegisterAlias("shortname", "min=2,max=5")
+	r.RegisterStructValidation(passwordsMatch, evenRequest{})
+	r.POST("/even", func(req *evenRequest) string {
+		return req.Name
+	})
+	r.GET("/even", func(q *evenQuery) string {
+		return "ok"
+	})
+
+	response := makeRequest(t, r, http.MethodPost, "/even", gin.H{"number": 4, "name": "abc"})
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	response = makeRequest(t, r, http.MethodPost, "/even", gin.H{"number": 3, "name": "abc"})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "field validation for 'number' failed on the 'even' tag")
+
+	response = makeRequest(t, r, http.MethodPost, "/even", gin.H{"number": 4, "name": "abcdef"})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "field validation for 'name' failed on the 'max' tag")
+
+	response = makeRequest(t, r, http.MethodGet, "/even?number=3", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "failed on the 'even' tag")
+
+	response = makeRequest(t, r, http.MethodPost, "/even", gin.H{"number": 4, "name": "abc", "password": "a", "repeated": "b"})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "field validation for 'repeated' failed on the 'eqfield' tag")
+
+	doc := generateDocs(t, r)
+	properties := doc.Paths["/even"].Post.RequestBody.Value.Content.Get("application/json").Schema.Value.Properties
+
+	number := properties["number"].Value
+	assert.Equal(t, 2.0, *number.MultipleOf)
+	assert.Equal(t, []interface{}{"even"}, number.Extensions["x-validations"])
+
+	name := properties["name"].Value
+	assert.Equal(t, uint64(2), name.MinLength)
+	assert.Equal(t, uint64(5), *name.MaxLength)
+}
+
+func TestValidatorIsolatedPerRouter(t *testing.T) {
+	type request struct {
+		Number int `json:"number" binding:"even"`
+	}
+
+	globalValidator := binding.Validator
+	custom := Router()
+	custom.RegisterValidation("even", isEven)
+	custom.POST("/even", func(req *request) string { return "ok" })
+
+	other := RouterWithOptions(&RouterOptions{DisableJSONTagNames: true})
+	type otherRequest struct {
+		Id int `json:"id" binding:"required"`
+	}
+	other.POST("/required", func(req *otherRequest) string { return "ok" })
+
+	response := makeRequest(t, custom, http.MethodPost, "/even", gin.H{"number": 3})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = makeRequest(t, other, http.MethodPost, "/required", gin.H{})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "field validation for 'Id' failed on the 'required' tag")
+
+	assert.Error(t, custom.Validator().Struct(request{Number: 1}))
+	assert.Panics(t, func() {
+		_ = other.Validator().Struct(request{Number: 1})
+	})
+	assert.Equal(t, globalValidator, binding.Validator)
+}
@@ -0,0 +1,47 @@
+package gnext
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/meteran/gnext/docs"
+)
+
+// Validator returns the validator of the router. Each router has its own instance, so its configuration
+// doesn't affect other routers nor Gin's global validator.
+// It can be used to configure the validator beyond the registration methods below.
+func (r *RootRouter) Validator() *validator.Validate {
+	return r.validate
+}
+
+// RegisterValidation registers a custom validation under the given tag, used in `binding` tags.
+// The tag is listed in the `x-validations` extension of the documented schemas. The optional document function
+// can describe the validation with the OpenAPI constraints, e.g. set a pattern or a minimum.
+//
+// Validations should be registered before the handlers using them, because the documentation is generated on registration.
+func (r *RootRouter) RegisterValidation(tag string, validation validator.Func, document ...docs.ValidationSchema) {
+	if len(document) > 1 {
+		panic(fmt.Sprintf("validation '%s' can have only one document function", tag))
+	}
+	if err := r.validate.RegisterValidation(tag, validation); err != nil {
+		panic(fmt.Sprintf("cannot register validation '%s': %s", tag, err))
+	}
+
+	var documentFunc docs.ValidationSchema
+	if len(document) == 1 {
+		documentFunc = document[0]
+	}
+	r.Docs.Schemas.RegisterValidation(tag, documentFunc)
+}
+
+// RegisterStructValidation registers a validation of the whole struct for the given types,
+// e.g. to check fields depending on each other.
+func (r *RootRouter) RegisterStructValidation(validation validator.StructLevelFunc, types ...interface{}) {
+	r.validate.RegisterStructValidation(validation, types...)
+}
+
+// RegisterAlias registers an alias for the validation tags, e.g. "iscolor" for "hexcolor|rgb|rgba".
+// The aliased tags are documented as if they were used directly.
+func (r *RootRouter) RegisterAlias(alias string, tags string) {
+	r.validate.RegisterAlias(alias, tags)
+	r.Docs.Schemas.RegisterAlias(alias, tags)
+}